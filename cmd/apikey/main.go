@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+	"simple-crud/internal/service"
+	"simple-crud/internal/telemetry"
+	"simple-crud/internal/version"
+)
+
+// apikey manages API keys straight in MongoDB, e.g. to issue the first admin
+// key before any caller could use the /admin/api-keys routes.
+const usage = `usage:
+  apikey create -name NAME -scopes a,b [-expires 720h]
+  apikey list
+  apikey rotate -id ID [-grace 24h]
+  apikey revoke -id ID
+`
+
+func fail(ctx context.Context, msg string, err error) {
+	logger.Error(ctx, msg,
+		slog.String("exception.message", err.Error()),
+		slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+		slog.String("exception.stacktrace", string(debug.Stack())),
+	)
+	os.Exit(1)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	name := fs.String("name", "", "key name, e.g. the calling service")
+	scopes := fs.String("scopes", "", "comma-separated scopes: products:read, products:write, admin")
+	expires := fs.Duration("expires", 0, "lifetime of the key (0 = never expires)")
+	id := fs.String("id", "", "key ID")
+	grace := fs.Duration("grace", 0, "how long the replaced key keeps working after rotate")
+	_ = fs.Parse(os.Args[2:])
+
+	bgCtx := context.Background()
+	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Instance()
+	cfg := config.Instance()
+
+	logger.Info(
+		globalCtx,
+		"Starting apikey",
+		slog.String("service.name", cfg.AppName),
+		slog.String("service.version", version.Version),
+		slog.String("service.git_version", version.Commit),
+		slog.String("service.build_time", version.BuildTime),
+		slog.String("data.command", cmd),
+	)
+
+	shutdown, _ := telemetry.Instance(globalCtx)
+	defer shutdown()
+
+	db, err := database.Instance(globalCtx, cfg.MongoURI, cfg.MongoDBName)
+	if err != nil {
+		fail(globalCtx, "Failed to connect to MongoDB", err)
+	}
+	apiKeyRepo, err := repository.NewAPIKeyRepository(globalCtx, db)
+	if err != nil {
+		fail(globalCtx, "Failed to prepare API key collection", err)
+	}
+	svc := service.NewAPIKeyService(apiKeyRepo)
+
+	switch cmd {
+	case "create":
+		key := &model.APIKey{Name: *name}
+		if *scopes != "" {
+			key.Scopes = strings.Split(*scopes, ",")
+		}
+		if *expires > 0 {
+			expiresAt := time.Now().UTC().Add(*expires)
+			key.ExpiresAt = &expiresAt
+		}
+		created, err := svc.Create(globalCtx, key)
+		if err != nil {
+			fail(globalCtx, "Failed to create API key", err)
+		}
+		printKey(created)
+	case "list":
+		keys, err := svc.GetAll(globalCtx)
+		if err != nil {
+			fail(globalCtx, "Failed to list API keys", err)
+		}
+		for i := range keys {
+			printKey(&keys[i])
+		}
+	case "rotate", "revoke":
+		if *id == "" {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		var key *model.APIKey
+		if cmd == "rotate" {
+			key, err = svc.Rotate(globalCtx, *id, *grace)
+		} else {
+			key, err = svc.Revoke(globalCtx, *id)
+		}
+		if err != nil {
+			fail(globalCtx, "Failed to "+cmd+" API key", err)
+		}
+		printKey(key)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func printKey(k *model.APIKey) {
+	state := "active"
+	switch {
+	case k.RevokedAt != nil:
+		state = "revoked"
+	case k.ExpiresAt != nil && !time.Now().Before(*k.ExpiresAt):
+		state = "expired"
+	}
+	fmt.Printf("%s  %-20s %-8s %s  scopes=%s\n", k.ID.Hex(), k.Name, state, k.Prefix, strings.Join(k.Scopes, ","))
+	if k.Key != "" {
+		fmt.Printf("  key: %s\n  (shown once; store it now)\n", k.Key)
+	}
+}
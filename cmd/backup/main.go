@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"simple-crud/internal/backup"
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/repository"
+	"simple-crud/internal/telemetry"
+	"simple-crud/internal/version"
+)
+
+const usage = `usage:
+  backup create  -o FILE [-collections a,b]
+  backup verify  -i FILE
+  backup restore -i FILE [-mode merge|replace] [-collections a,b]
+`
+
+// auxiliaryCollections are archived verbatim next to the products.
+var auxiliaryCollections = []string{
+	"price_schedule",
+	"price_history",
+	"webhook_subscription",
+	"webhook_delivery",
+	"api_key",
+	"product_files.files",
+	"product_files.chunks",
+}
+
+func fail(ctx context.Context, msg string, err error) {
+	logger.Error(ctx, msg,
+		slog.String("exception.message", err.Error()),
+		slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+		slog.String("exception.stacktrace", string(debug.Stack())),
+	)
+	os.Exit(1)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	output := fs.String("o", "", "archive to write")
+	input := fs.String("i", "", "archive to read")
+	modeFlag := fs.String("mode", string(backup.ModeMerge), "restore mode: merge (upsert by ID) or replace (clear first)")
+	only := fs.String("collections", "", "comma-separated collections to include (default all)")
+	_ = fs.Parse(os.Args[2:])
+
+	// Create cancellable context so Ctrl-C stops the run cleanly
+	bgCtx := context.Background()
+	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Instance()
+	cfg := config.Instance()
+
+	logger.Info(
+		globalCtx,
+		"Starting backup",
+		slog.String("service.name", cfg.AppName),
+		slog.String("service.version", version.Version),
+		slog.String("service.git_version", version.Commit),
+		slog.String("service.build_time", version.BuildTime),
+		slog.String("data.command", cmd),
+	)
+
+	shutdown, _ := telemetry.Instance(globalCtx)
+	defer shutdown()
+
+	// verify needs no database
+	if cmd == "verify" {
+		if *input == "" {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		f, err := os.Open(*input)
+		if err != nil {
+			fail(globalCtx, "Failed to open archive", err)
+		}
+		defer f.Close()
+		m, err := backup.Verify(globalCtx, f)
+		if err != nil {
+			fail(globalCtx, "Archive verification failed", err)
+		}
+		printManifest(m)
+		return
+	}
+
+	db, err := database.Instance(globalCtx, cfg.MongoURI, cfg.MongoDBName)
+	if err != nil {
+		fail(globalCtx, "Failed to connect to MongoDB", err)
+	}
+	collections := []backup.Collection{
+		backup.NewProductCollection(repository.NewProductRepository(db)),
+	}
+	for _, name := range auxiliaryCollections {
+		collections = append(collections, backup.NewMongoCollection(db.Database, name))
+	}
+	var selected []string
+	if *only != "" {
+		selected = strings.Split(*only, ",")
+	}
+
+	switch cmd {
+	case "create":
+		if *output == "" {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		if selected != nil {
+			collections = filterCollections(collections, selected)
+		}
+		// Write next to the target and rename, so a failed run never leaves
+		// a truncated archive under the final name.
+		tmp, err := os.CreateTemp(filepath.Dir(*output), ".backup-*")
+		if err != nil {
+			fail(globalCtx, "Failed to create archive", err)
+		}
+		m, err := backup.Create(globalCtx, tmp, collections)
+		if cerr := tmp.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), *output)
+		}
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+			fail(globalCtx, "Failed to create backup", err)
+		}
+		printManifest(m)
+	case "restore":
+		if *input == "" {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		mode, err := backup.ParseMode(*modeFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		f, err := os.Open(*input)
+		if err != nil {
+			fail(globalCtx, "Failed to open archive", err)
+		}
+		defer f.Close()
+		m, err := backup.Restore(globalCtx, f, collections, mode, selected)
+		if err != nil {
+			fail(globalCtx, "Failed to restore backup", err)
+		}
+		logger.Info(globalCtx, "Restore finished",
+			slog.String("data.mode", string(mode)),
+			slog.Int("data.collections", len(m.Collections)),
+		)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func filterCollections(all []backup.Collection, names []string) []backup.Collection {
+	var out []backup.Collection
+	for _, name := range names {
+		found := false
+		for _, c := range all {
+			if c.Name() == name {
+				out = append(out, c)
+				found = true
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "unknown collection %q\n", name)
+			os.Exit(2)
+		}
+	}
+	return out
+}
+
+func printManifest(m *backup.Manifest) {
+	fmt.Printf("%s v%d created %s by %s\n", m.Format, m.Version, m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"), m.AppVersion)
+	for _, c := range m.Collections {
+		fmt.Printf("  %-24s %8d records %10d bytes  sha256:%s\n", c.Name, c.Count, c.Bytes, c.SHA256)
+	}
+}
@@ -10,21 +10,23 @@ import (
 	"math/rand"
 	"net"
 	"os"
-	"os/signal"
 	"runtime/debug"
 	"sort"
 	"strings"
-	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
 	grpcCodes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // enables client-side health checking
 	"google.golang.org/grpc/metadata"
 	grpcStatus "google.golang.org/grpc/status"
 
+	"simple-crud/internal/auth"
+	"simple-crud/internal/compression"
 	"simple-crud/internal/config"
 	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/lifecycle"
 	"simple-crud/internal/logger"
 	"simple-crud/internal/telemetry"
 	"simple-crud/internal/version"
@@ -56,11 +58,30 @@ func resolvePods(host string) ([]string, error) {
 // connect establishes gRPC client connection to the backend server using round_robin policy.
 func connect(globalCtx context.Context, target string) error {
 	var err error
-	conn, err = grpc.NewClient(
-		target,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-	)
+		// round_robin only picks backends whose health Watch reports
+		// SERVING, so a draining or Mongo-less pod stops getting calls
+		// before its address leaves DNS.
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(
+			`{"loadBalancingPolicy":"round_robin","healthCheckConfig":{"serviceName":%q}}`,
+			pb.ProductService_ServiceDesc.ServiceName,
+		)),
+	}
+	if cfg.ClientAPIKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.APIKeyCredentials(cfg.ClientAPIKey)))
+	}
+	compressor, err := compression.DialOption(cfg.GRPCCompression)
+	if err != nil {
+		logger.Error(globalCtx, "Invalid GRPC_COMPRESSION",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		return err
+	}
+	opts = append(opts, compressor)
+	conn, err = grpc.NewClient(target, opts...)
 	if err != nil {
 		logger.Error(globalCtx, "Failed to connect to gRPC server",
 			slog.String("grpc.remote_addr", cfg.ExternalGRPC),
@@ -236,11 +257,13 @@ func grpcWorker(globalCtx context.Context, notify chan struct{}) {
 
 func main() {
 	logger.Instance()
-	bgCtx := context.Background()
-	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	globalCtx := context.Background()
 
-	isProduction := os.Getenv("ENV") == "production"
+	// Nothing routes to a client, so it stops without draining.
+	app := lifecycle.New(lifecycle.Config{
+		HookTimeout: time.Duration(cfg.ShutdownHookTimeoutMs) * time.Millisecond,
+	})
+	app.Append(lifecycle.Hook{Name: "logger", OnStop: logger.Flush})
 
 	logger.Info(globalCtx,
 		"Starting gRPC client",
@@ -248,30 +271,24 @@ func main() {
 		slog.String("service.version", version.Version),
 		slog.String("service.git_version", version.Commit),
 		slog.String("service.build_time", version.BuildTime),
-		slog.Bool("service.gracefull_shutdown", isProduction),
 	)
 
-	_, _ = telemetry.Instance(globalCtx) // OpenTelemetry setup (no shutdown handling here since it's long-lived client)
+	_, _ = telemetry.Instance(globalCtx)
+	app.Append(lifecycle.Hook{Name: "telemetry", OnStop: telemetry.Shutdown})
 
 	notify := make(chan struct{}, 1)
 
-	// Start DNS watcher goroutine
-	go dnsWatcher(globalCtx, cfg.ExternalGRPC, notify)
-
-	// Start gRPC worker loop
-	go func() {
-		grpcWorker(globalCtx, notify)
-	}()
-
-	// Wait for shutdown signal
-	<-globalCtx.Done()
-
-	if !isProduction {
-		logger.Info(globalCtx, "Received shutdown signal, exiting immediately")
-		os.Exit(0)
-	} else {
-		logger.Info(globalCtx, "Shutting down gRPC client")
-		// Let goroutines finish naturally via context cancellation
-		time.Sleep(100 * time.Millisecond) // Brief wait for cleanup
+	// The worker closes its connection when it returns. It is registered
+	// before the DNS watcher so the watcher stops first and never blocks
+	// notifying a worker that is gone.
+	app.Go("grpc-worker", func(ctx context.Context) {
+		grpcWorker(ctx, notify)
+	})
+	app.Go("dns-watcher", func(ctx context.Context) {
+		dnsWatcher(ctx, cfg.ExternalGRPC, notify)
+	})
+
+	if err := app.Run(globalCtx); err != nil {
+		os.Exit(1)
 	}
 }
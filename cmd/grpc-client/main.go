@@ -3,13 +3,13 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"math/rand"
 	"os"
-	"os/signal"
 	"runtime/debug"
-	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
@@ -19,8 +19,11 @@ import (
 	grpcStatus "google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"simple-crud/internal/auth"
+	"simple-crud/internal/compression"
 	"simple-crud/internal/config"
 	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/lifecycle"
 	"simple-crud/internal/logger"
 	"simple-crud/internal/telemetry"
 	"simple-crud/internal/version"
@@ -28,16 +31,48 @@ import (
 	"go.opentelemetry.io/otel"
 )
 
+var (
+	streamMode = flag.Bool("stream", false, "use the StreamProducts RPC instead of GetAll")
+	chunkSize  = flag.Int("chunk-size", 0, "products per chunk for -stream (0 = server default)")
+)
+
+// streamProducts drains StreamProducts and returns the number of products
+// and chunks received.
+func streamProducts(ctx context.Context, client pb.ProductServiceClient, trailer *metadata.MD) (int, int, error) {
+	stream, err := client.StreamProducts(ctx, &pb.StreamProductsReq{ChunkSize: int32(*chunkSize)})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var products, chunks int
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			*trailer = stream.Trailer()
+			return products, chunks, nil
+		}
+		if err != nil {
+			*trailer = stream.Trailer()
+			return products, chunks, err
+		}
+		products += len(chunk.GetProducts())
+		chunks++
+	}
+}
+
 func main() {
-	// Create cancellable context for graceful shutdown
-	bgCtx := context.Background()
-	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	flag.Parse()
+
+	globalCtx := context.Background()
 
 	logger.Instance()
 	cfg := config.Instance()
 
-	isProduction := os.Getenv("ENV") == "production"
+	// Nothing routes to a client, so it stops without draining.
+	app := lifecycle.New(lifecycle.Config{
+		HookTimeout: time.Duration(cfg.ShutdownHookTimeoutMs) * time.Millisecond,
+	})
+	app.Append(lifecycle.Hook{Name: "logger", OnStop: logger.Flush})
 
 	logger.Info(
 		globalCtx,
@@ -46,19 +81,31 @@ func main() {
 		slog.String("service.version", version.Version),
 		slog.String("service.git_version", version.Commit),
 		slog.String("service.build_time", version.BuildTime),
-		slog.Bool("service.gracefull_shutdown", isProduction),
 	)
 
-	shutdown, _ := telemetry.Instance(globalCtx)
-	defer shutdown()
+	_, _ = telemetry.Instance(globalCtx)
+	app.Append(lifecycle.Hook{Name: "telemetry", OnStop: telemetry.Shutdown})
 
 	tracer := otel.Tracer("backend-grpc-client")
 
-	conn, err := grpc.NewClient(
-		cfg.ExternalGRPC,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-	)
+	}
+	if cfg.ClientAPIKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.APIKeyCredentials(cfg.ClientAPIKey)))
+	}
+	compressor, err := compression.DialOption(cfg.GRPCCompression)
+	if err != nil {
+		logger.Error(globalCtx, "Invalid GRPC_COMPRESSION",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	opts = append(opts, compressor)
+	conn, err := grpc.NewClient(cfg.ExternalGRPC, opts...)
 	if err != nil {
 		logger.Error(globalCtx, "Failed to connect to gRPC server",
 			slog.String("grpc.remote_addr", cfg.ExternalGRPC),
@@ -68,10 +115,13 @@ func main() {
 		)
 		os.Exit(1)
 	}
-	defer func() {
-		logger.Info(globalCtx, "Closing gRPC connection")
-		_ = conn.Close()
-	}()
+	app.Append(lifecycle.Hook{
+		Name: "grpc-conn",
+		OnStop: func(ctx context.Context) error {
+			logger.Info(ctx, "Closing gRPC connection")
+			return conn.Close()
+		},
+	})
 
 	client := pb.NewProductServiceClient(conn)
 
@@ -81,90 +131,129 @@ func main() {
 		slog.String("data.target", cfg.ExternalGRPC),
 		slog.Int("data.max_client_delay", int(cfg.ClientMaxSleepMs)),
 		slog.Int("data.dns_resolver_delay", int(cfg.DnsResolverDelayMs)),
+		slog.Bool("data.stream", *streamMode),
 	)
 
-	for {
-		select {
-		case <-globalCtx.Done():
-			if !isProduction {
-				logger.Info(globalCtx, "Received shutdown signal, exiting immediately")
-				os.Exit(0)
-			} else {
+	// The request loop stops, finishing the request in flight, when the
+	// manager stops its hook.
+	app.Go("grpc-requester", func(globalCtx context.Context) {
+		for {
+			select {
+			case <-globalCtx.Done():
 				logger.Info(globalCtx, "Shutting down gRPC client")
 				return
-			}
 
-		default:
-			// Add span tracing
-			ctx, cancel := context.WithTimeout(globalCtx, 3*time.Second)
-			defer cancel()
-
-			// --- start parent span
-			ctx, span := tracer.Start(ctx, "backend-grpc-request")
-			defer span.End()
-
-			// --- inject trace context to metadata ─────────────────────────
-			md := metadata.New(nil)
-			otel.GetTextMapPropagator().Inject(ctx, telemetry.MetadataTextMapCarrier(md))
-			ctx = metadata.NewOutgoingContext(ctx, md)
-
-			// fmt.Println(md.Get("traceparent")[0])
-			// fmt.Println(span.SpanContext().TraceID().String())
-
-			fullMethod := "/simplecrud.ProductService/GetAll"
-			reqMsg := &emptypb.Empty{}
-			attrs := logger.LogGRPCRequest(ctx, fullMethod, md, reqMsg, "outgoing::request")
-			logger.Info(ctx, "GRPC", attrs...)
-
-			// --- call GetAll RPC with metadata ────────────────────────────
-			start := time.Now()
-			var trailer metadata.MD
-			resp, err := client.GetAll(ctx, &emptypb.Empty{}, grpc.Trailer(&trailer))
-			cancel()
-			span.End()
-			duration := time.Since(start)
-
-			var grpcCode grpcCodes.Code
-			if err != nil {
-				if st, ok := grpcStatus.FromError(err); ok {
-					grpcCode = st.Code()
+			default:
+				// Add span tracing
+				ctx, cancel := context.WithTimeout(globalCtx, 3*time.Second)
+				defer cancel()
+
+				// --- start parent span
+				ctx, span := tracer.Start(ctx, "backend-grpc-request")
+				defer span.End()
+
+				// --- inject trace context to metadata ─────────────────────────
+				md := metadata.New(nil)
+				otel.GetTextMapPropagator().Inject(ctx, telemetry.MetadataTextMapCarrier(md))
+				ctx = metadata.NewOutgoingContext(ctx, md)
+
+				// fmt.Println(md.Get("traceparent")[0])
+				// fmt.Println(span.SpanContext().TraceID().String())
+
+				if *streamMode {
+					fullMethod := pb.ProductService_StreamProducts_FullMethodName
+					reqMsg := &pb.StreamProductsReq{ChunkSize: int32(*chunkSize)}
+					attrs := logger.LogGRPCRequest(ctx, fullMethod, md, reqMsg, "outgoing::request")
+					logger.Info(ctx, "GRPC", attrs...)
+
+					start := time.Now()
+					var trailer metadata.MD
+					count, chunks, err := streamProducts(ctx, client, &trailer)
+					cancel()
+					span.End()
+					duration := time.Since(start)
+
+					attrs = logger.LogGRPCResponse(ctx, fullMethod, trailer, int32(grpcStatus.Code(err)), nil, duration, "outgoing::response")
+					logger.Info(ctx, "GRPC", attrs...)
+
+					if err != nil {
+						logger.Error(ctx, "Error calling StreamProducts",
+							slog.String("exception.message", err.Error()),
+							slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+							slog.String("exception.stacktrace", string(debug.Stack())),
+							slog.Int("data.count", count),
+						)
+					} else {
+						logger.Info(ctx, "Received products",
+							slog.Int("data.count", count),
+							slog.Int("data.chunks", chunks),
+						)
+					}
+
+					delay := time.Duration(rand.Intn(int(cfg.ClientMaxSleepMs))+1) * time.Millisecond
+					time.Sleep(delay)
+					continue
+				}
+
+				fullMethod := "/simplecrud.ProductService/GetAll"
+				reqMsg := &emptypb.Empty{}
+				attrs := logger.LogGRPCRequest(ctx, fullMethod, md, reqMsg, "outgoing::request")
+				logger.Info(ctx, "GRPC", attrs...)
+
+				// --- call GetAll RPC with metadata ────────────────────────────
+				start := time.Now()
+				var trailer metadata.MD
+				resp, err := client.GetAll(ctx, &emptypb.Empty{}, grpc.Trailer(&trailer))
+				cancel()
+				span.End()
+				duration := time.Since(start)
+
+				var grpcCode grpcCodes.Code
+				if err != nil {
+					if st, ok := grpcStatus.FromError(err); ok {
+						grpcCode = st.Code()
+					} else {
+						grpcCode = grpcCodes.Unknown // fallback: 2
+					}
 				} else {
-					grpcCode = grpcCodes.Unknown // fallback: 2
+					grpcCode = grpcCodes.OK // success: 0
 				}
-			} else {
-				grpcCode = grpcCodes.OK // success: 0
-			}
-			status := int32(grpcCode)
+				status := int32(grpcCode)
 
-			attrs = logger.LogGRPCResponse(ctx, fullMethod, trailer, status, resp, duration, "outgoing::response")
-			logger.Info(ctx, "GRPC", attrs...)
+				attrs = logger.LogGRPCResponse(ctx, fullMethod, trailer, status, resp, duration, "outgoing::response")
+				logger.Info(ctx, "GRPC", attrs...)
 
-			// If the server sets the x-trace-id in the trailer, we can log it
-			serverTraceID := "empty"
-			if ids := trailer.Get("x-trace-id"); len(ids) > 0 {
-				serverTraceID = ids[0]
-			} else {
-				logger.Warn(ctx, "No Trace ID received")
-			}
+				// If the server sets the x-trace-id in the trailer, we can log it
+				serverTraceID := "empty"
+				if ids := trailer.Get("x-trace-id"); len(ids) > 0 {
+					serverTraceID = ids[0]
+				} else {
+					logger.Warn(ctx, "No Trace ID received")
+				}
 
-			// --- logging response / error ────────────────────────────────────
-			if err != nil {
-				logger.Error(ctx, "Error calling GetAll",
-					slog.String("exception.message", err.Error()),
-					slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
-					slog.String("exception.stacktrace", string(debug.Stack())),
-					slog.String("data.trace_id", serverTraceID),
-				)
-			} else {
-				logger.Info(ctx, "Received products",
-					slog.String("data.resolver", resp.Resolver),
-					slog.Int("data.count", len(resp.GetProducts())),
-					slog.String("data.trace_id", serverTraceID),
-				)
-			}
+				// --- logging response / error ────────────────────────────────────
+				if err != nil {
+					logger.Error(ctx, "Error calling GetAll",
+						slog.String("exception.message", err.Error()),
+						slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+						slog.String("exception.stacktrace", string(debug.Stack())),
+						slog.String("data.trace_id", serverTraceID),
+					)
+				} else {
+					logger.Info(ctx, "Received products",
+						slog.String("data.resolver", resp.Resolver),
+						slog.Int("data.count", len(resp.GetProducts())),
+						slog.String("data.trace_id", serverTraceID),
+					)
+				}
 
-			delay := time.Duration(rand.Intn(int(cfg.ClientMaxSleepMs))+1) * time.Millisecond
-			time.Sleep(delay)
+				delay := time.Duration(rand.Intn(int(cfg.ClientMaxSleepMs))+1) * time.Millisecond
+				time.Sleep(delay)
+			}
 		}
+	})
+
+	if err := app.Run(globalCtx); err != nil {
+		os.Exit(1)
 	}
 }
@@ -6,36 +6,46 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
-	"os/signal"
 	"runtime/debug"
-	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	_ "simple-crud/internal/compression" // gzip and zstd, answered in kind
 	"simple-crud/internal/config"
 	"simple-crud/internal/database"
 	grpcHandler "simple-crud/internal/handler/grpc"
 	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/health"
+	"simple-crud/internal/lifecycle"
+	"simple-crud/internal/loadshed"
 	"simple-crud/internal/logger"
+	"simple-crud/internal/metrics"
 	middleware_grpc "simple-crud/internal/middleware/grpc"
+	"simple-crud/internal/policy"
+	"simple-crud/internal/ratelimit"
 	"simple-crud/internal/repository"
 	"simple-crud/internal/service"
+	"simple-crud/internal/storage"
 	"simple-crud/internal/telemetry"
 	"simple-crud/internal/version"
+	"simple-crud/internal/worker"
 )
 
 func main() {
-	// Create cancellable context for graceful shutdown
-	bgCtx := context.Background()
-	globalCtx, cancel := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+	globalCtx := context.Background()
 
 	logger.Instance()
 	cfg := config.Instance()
 
-	isProduction := os.Getenv("ENV") == "production"
+	// Components register with the lifecycle manager as they are wired: it
+	// stops them in reverse order, the server first and the log shipper last.
+	app := lifecycle.New(lifecycle.NewConfig(cfg))
+	app.Append(lifecycle.Hook{Name: "logger", OnStop: logger.Flush})
 
 	logger.Info(
 		globalCtx,
@@ -44,12 +54,12 @@ func main() {
 		slog.String("service.version", version.Version),
 		slog.String("service.git_version", version.Commit),
 		slog.String("service.build_time", version.BuildTime),
-		slog.Bool("service.gracefull_shutdown", isProduction),
+		slog.Int64("service.shutdown_drain_ms", cfg.ShutdownDrainMs),
 	)
 
 	// Initialize telemetry (OpenTelemetry + Pyroscope)
-	shutdown, _ := telemetry.Instance(globalCtx)
-	defer shutdown()
+	_, _ = telemetry.Instance(globalCtx)
+	app.Append(lifecycle.Hook{Name: "telemetry", OnStop: telemetry.Shutdown})
 
 	// Connect to MongoDB
 	db, err := database.Instance(globalCtx, cfg.MongoURI, cfg.MongoDBName)
@@ -61,52 +71,237 @@ func main() {
 		)
 		os.Exit(1)
 	}
+	app.Append(lifecycle.Hook{Name: "mongo", OnStop: db.Close})
 
 	// Wiring
-	productRepo := repository.NewProductRepository(db.Database)
+	productRepo := repository.NewProductRepository(db)
 	productService := service.NewProductService(productRepo)
 	productHandler := grpcHandler.NewProductGRPCHandler(productService)
 
-	// Start gRPC server
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware_grpc.UnaryTracingInterceptor()),
+	// Attachments are uploaded over HTTP; gRPC only needs the store to clean
+	// up after deleted products.
+	blobStore, err := storage.NewBlobStore(cfg, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to initialize attachment store",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	productService.SetBlobStore(blobStore)
+
+	reportRepo := repository.NewReportRepository(db)
+	reportService := service.NewReportService(reportRepo,
+		time.Duration(cfg.ReportCacheTTLSec)*time.Second,
+		time.Duration(cfg.ReportTimeoutMs)*time.Millisecond,
 	)
-	pb.RegisterProductServiceServer(grpcServer, productHandler)
-	reflection.Register(grpcServer)
+	reportService.SetProductGuard(productService)
+	reportHandler := grpcHandler.NewReportGRPCHandler(reportService)
 
-	lis, err := net.Listen("tcp", ":"+cfg.AppPort)
+	priceRepo, err := repository.NewPriceRepository(globalCtx, db)
 	if err != nil {
-		logger.Error(globalCtx, "Failed to listen",
+		logger.Error(globalCtx, "Failed to prepare price collections",
 			slog.String("exception.message", err.Error()),
 			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
 			slog.String("exception.stacktrace", string(debug.Stack())),
 		)
 		os.Exit(1)
 	}
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	priceHandler := grpcHandler.NewPriceGRPCHandler(priceService)
+	productService.SetPriceHistory(priceService)
+	priceService.SetProductGuard(productService)
+	if cfg.PriceSchedulerEnabled {
+		app.Go("price-scheduler", worker.NewPriceScheduler(priceRepo, productRepo, cfg).Run)
+	}
 
-	logger.Info(globalCtx, "gRPC server running", slog.String("port", cfg.AppPort))
+	webhookRepo, err := repository.NewWebhookRepository(globalCtx, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to prepare webhook collections",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	webhookService := service.NewWebhookService(webhookRepo)
+	webhookHandler := grpcHandler.NewWebhookGRPCHandler(webhookService)
+	productService.SetEventPublisher(webhookService)
+	if cfg.WebhookWorkerEnabled {
+		app.Go("webhook-worker", worker.NewWebhookWorker(webhookRepo, worker.NewWebhookWorkerConfig(cfg)).Run)
+	}
 
-	// Run gRPC server in background
-	go func() {
-		if err := grpcServer.Serve(lis); err != nil {
-			logger.Error(globalCtx, "Failed to serve",
+	// Wiring the role policy: product actions are authorized in the
+	// service, so HTTP and gRPC enforce the same rules.
+	if cfg.PolicyFile != "" {
+		policyStore, err := policy.NewStore(cfg.PolicyFile)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to load policy",
 				slog.String("exception.message", err.Error()),
 				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
 				slog.String("exception.stacktrace", string(debug.Stack())),
 			)
 			os.Exit(1)
 		}
-	}()
-
-	// Wait for shutdown signal
-	<-globalCtx.Done()
-
-	if !isProduction {
-		logger.Info(globalCtx, "Received shutdown signal, exiting immediately")
-		os.Exit(0)
-	} else {
-		logger.Info(globalCtx, "Shutting down gRPC server")
-		grpcServer.GracefulStop()
-		logger.Info(globalCtx, "gRPC server exited cleanly")
+		productService.SetAuthorizer(policyStore)
+		app.Go("policy-watch", func(ctx context.Context) {
+			policyStore.Watch(ctx, time.Duration(cfg.PolicyReloadIntervalMs)*time.Millisecond)
+		})
+	}
+
+	apiKeyRepo, err := repository.NewAPIKeyRepository(globalCtx, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to prepare API key collection",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	apiKeyHandler := grpcHandler.NewAPIKeyGRPCHandler(apiKeyService)
+
+	// With authentication on, credentials are verified ahead of tracing so the
+	// request log carries the subject, and rejected after it so the
+	// Unauthenticated error is traced.
+	unary := []grpc.UnaryServerInterceptor{middleware_grpc.UnaryTracingInterceptor()}
+	stream := []grpc.StreamServerInterceptor{middleware_grpc.StreamTracingInterceptor()}
+	if cfg.AuthEnabled {
+		authenticator, err := service.NewAuthenticator(cfg, apiKeyService)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to initialize authentication",
+				slog.String("exception.message", err.Error()),
+				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+				slog.String("exception.stacktrace", string(debug.Stack())),
+			)
+			os.Exit(1)
+		}
+		unary = []grpc.UnaryServerInterceptor{
+			middleware_grpc.UnaryAuthInterceptor(authenticator),
+			middleware_grpc.UnaryTracingInterceptor(),
+			middleware_grpc.UnaryRequireAuthInterceptor(),
+		}
+		stream = []grpc.StreamServerInterceptor{
+			middleware_grpc.StreamAuthInterceptor(authenticator),
+			middleware_grpc.StreamTracingInterceptor(),
+			middleware_grpc.StreamRequireAuthInterceptor(),
+		}
+	}
+	// Rate limiting keys clients by the principal the auth interceptors
+	// attached, so it follows them; load shedding follows rate limiting so
+	// rate-limited calls never take a concurrency slot.
+	if cfg.RateLimitEnabled {
+		limiter, err := ratelimit.NewLimiterFromConfig(globalCtx, cfg, db)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to initialize rate limiting",
+				slog.String("exception.message", err.Error()),
+				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+				slog.String("exception.stacktrace", string(debug.Stack())),
+			)
+			os.Exit(1)
+		}
+		unary = append(unary, middleware_grpc.UnaryRateLimitInterceptor(limiter))
+		stream = append(stream, middleware_grpc.StreamRateLimitInterceptor(limiter))
+	}
+	if cfg.LoadShedEnabled {
+		limiter := loadshed.NewLimiter(loadshed.NewConfig(cfg))
+		unary = append(unary, middleware_grpc.UnaryLoadShedInterceptor(limiter))
+		stream = append(stream, middleware_grpc.StreamLoadShedInterceptor(limiter))
+	}
+	unary = append(unary, middleware_grpc.UnaryMongoSessionInterceptor(db))
+
+	// Start gRPC server
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(int(cfg.GRPCMaxRecvMsgBytes)),
+		grpc.MaxSendMsgSize(int(cfg.GRPCMaxSendMsgBytes)),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	pb.RegisterProductServiceServer(grpcServer, productHandler)
+	pb.RegisterReportServiceServer(grpcServer, reportHandler)
+	pb.RegisterWebhookServiceServer(grpcServer, webhookHandler)
+	pb.RegisterPriceServiceServer(grpcServer, priceHandler)
+	pb.RegisterApiKeyServiceServer(grpcServer, apiKeyHandler)
+
+	// grpc.health.v1 reports the server ("") and each service above from the
+	// same readiness checks as the HTTP probes.
+	services := make([]string, 0, len(grpcServer.GetServiceInfo()))
+	for name := range grpcServer.GetServiceInfo() {
+		services = append(services, name)
+	}
+	healthService := service.NewHealthService(health.NewRegistryFromConfig(cfg, db, app))
+	healthHandler := grpcHandler.NewHealthGRPCHandler(healthService, services,
+		time.Duration(cfg.GRPCHealthIntervalMs)*time.Millisecond,
+	)
+	healthpb.RegisterHealthServer(grpcServer, healthHandler)
+	reflection.Register(grpcServer)
+
+	// Prometheus scrapes /metrics on its own port, since the main one speaks
+	// only gRPC. Registered first, it stops after the gRPC server.
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("GET /metrics", metrics.Handler())
+	metricsServer := &http.Server{
+		Addr:              ":" + cfg.MetricsPort,
+		Handler:           metricsMux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	app.Append(lifecycle.Hook{
+		Name: "metrics-server",
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", metricsServer.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Info(globalCtx, "Metrics server running", slog.String("data.addr", metricsServer.Addr))
+				if err := metricsServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+					app.Fail(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: metricsServer.Shutdown,
+	})
+
+	app.Append(lifecycle.Hook{
+		Name: "grpc-server",
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", ":"+cfg.AppPort)
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Info(globalCtx, "gRPC server running", slog.String("port", cfg.AppPort))
+				if err := grpcServer.Serve(lis); err != nil {
+					app.Fail(err)
+				}
+			}()
+			return nil
+		},
+		// GracefulStop waits for in-flight calls (and open streams) without a
+		// deadline, so calls still running when the hook times out are cut.
+		OnStop: func(ctx context.Context) error {
+			done := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				grpcServer.Stop()
+				return ctx.Err()
+			}
+		},
+	})
+
+	// Registered after the server so it stops first: Watch streams end and
+	// do not hold up the graceful stop.
+	app.Go("grpc-health", healthHandler.Run)
+
+	if err := app.Run(globalCtx); err != nil {
+		os.Exit(1)
 	}
 }
@@ -6,12 +6,11 @@ import (
 	"log/slog"
 	"math/rand"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"simple-crud/internal/client"
 	"simple-crud/internal/config"
+	"simple-crud/internal/lifecycle"
 	"simple-crud/internal/logger"
 	"simple-crud/internal/telemetry"
 	"simple-crud/internal/version"
@@ -20,15 +19,16 @@ import (
 )
 
 func main() {
-	// Create cancellable context for graceful shutdown
-	bgCtx := context.Background()
-	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	globalCtx := context.Background()
 
 	logger.Instance()
 	cfg := config.Instance()
 
-	isProduction := os.Getenv("ENV") == "production"
+	// Nothing routes to a client, so it stops without draining.
+	app := lifecycle.New(lifecycle.Config{
+		HookTimeout: time.Duration(cfg.ShutdownHookTimeoutMs) * time.Millisecond,
+	})
+	app.Append(lifecycle.Hook{Name: "logger", OnStop: logger.Flush})
 
 	logger.Info(
 		globalCtx,
@@ -37,55 +37,61 @@ func main() {
 		slog.String("service.version", version.Version),
 		slog.String("service.git_version", version.Commit),
 		slog.String("service.build_time", version.BuildTime),
-		slog.Bool("service.gracefull_shutdown", isProduction),
 	)
 
-	shutdown, _ := telemetry.Instance(globalCtx)
-	defer shutdown()
+	_, _ = telemetry.Instance(globalCtx)
+	app.Append(lifecycle.Hook{Name: "telemetry", OnStop: telemetry.Shutdown})
 
 	HttpRequestorTracer := otel.Tracer("HttpRequestorMain")
 
-	for {
-		select {
-		case <-globalCtx.Done():
-			if !isProduction {
-				logger.Info(globalCtx, "Received shutdown signal, exiting immediately")
-				os.Exit(0)
-			} else {
+	// The request loop stops, finishing the request in flight, when the
+	// manager stops its hook.
+	app.Go("http-requester", func(globalCtx context.Context) {
+		for {
+			select {
+			case <-globalCtx.Done():
 				logger.Info(globalCtx, "Shutting down HTTP client")
 				return
-			}
 
-		default:
-			ctx, cancel := context.WithTimeout(globalCtx, 2*time.Second)
-			ctx, span := HttpRequestorTracer.Start(ctx, "backend-http-request")
+			default:
+				ctx, cancel := context.WithTimeout(globalCtx, 2*time.Second)
+				ctx, span := HttpRequestorTracer.Start(ctx, "backend-http-request")
+
+				cfg := config.Instance()
+				httpClient := client.NewHTTPClient(cfg.ExternalHTTP, 3*time.Second)
+				if cfg.ClientAPIKey != "" {
+					httpClient.SetDefaultHeader("X-API-Key", cfg.ClientAPIKey)
+				}
 
-			cfg := config.Instance()
-			httpClient := client.NewHTTPClient(cfg.ExternalHTTP, 3*time.Second)
+				paths := []string{"/external", "/products", "/just-not-found"}
+				path := paths[rand.Intn(len(paths))]
+				resp, err := httpClient.GetWithResponse(path, client.RequestOptions{
+					Context: ctx,
+				})
+				if err != nil {
+					cancel()
+					span.End()
+					sleep(cfg)
+					continue
+				}
 
-			paths := []string{"/external", "/products", "/just-not-found"}
-			path := paths[rand.Intn(len(paths))]
-			resp, err := httpClient.GetWithResponse(path, client.RequestOptions{
-				Context: ctx,
-			})
-			if err != nil {
+				var products []interface{}
+				if err := json.Unmarshal(resp.RawBody, &products); err == nil {
+					logger.Info(
+						ctx,
+						"Fetched products",
+						slog.Int("count", len(products)),
+					)
+				}
 				cancel()
 				span.End()
 				sleep(cfg)
-				continue
 			}
-
-			var products []interface{}
-			if err := json.Unmarshal(resp.RawBody, &products); err == nil {
-				logger.Info(
-					ctx,
-					"Fetched products",
-					slog.Int("count", len(products)),
-				)
-			}
-			span.End()
-			sleep(cfg)
 		}
+	})
+
+	if err := app.Run(globalCtx); err != nil {
+		os.Exit(1)
 	}
 }
 
@@ -2,26 +2,33 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"os/signal"
 	"runtime/debug"
-	"syscall"
 	"time"
 
 	"simple-crud/internal/config"
 	"simple-crud/internal/database"
+	grpcHandler "simple-crud/internal/handler/grpc"
 	handler "simple-crud/internal/handler/http"
+	"simple-crud/internal/health"
+	"simple-crud/internal/lifecycle"
+	"simple-crud/internal/loadshed"
 	"simple-crud/internal/logger"
+	"simple-crud/internal/metrics"
 	middleware_http "simple-crud/internal/middleware/http"
+	"simple-crud/internal/policy"
+	"simple-crud/internal/ratelimit"
 	"simple-crud/internal/repository"
 	"simple-crud/internal/service"
+	"simple-crud/internal/storage"
 	"simple-crud/internal/telemetry"
 	"simple-crud/internal/version"
+	"simple-crud/internal/worker"
 )
 
 func Chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
@@ -32,16 +39,15 @@ func Chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.
 }
 
 func main() {
-	// Create cancellable context for graceful shutdown
-	bgCtx := context.Background()
-	globalCtx, cancel := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+	globalCtx := context.Background()
 
 	logger.Instance()
 	cfg := config.Instance()
 
-	// Check if ENV is production to enable graceful shutdown
-	isProduction := os.Getenv("ENV") == "production"
+	// Components register with the lifecycle manager as they are wired: it
+	// stops them in reverse order, servers first and the log shipper last.
+	app := lifecycle.New(lifecycle.NewConfig(cfg))
+	app.Append(lifecycle.Hook{Name: "logger", OnStop: logger.Flush})
 
 	logger.Info(
 		globalCtx,
@@ -50,12 +56,12 @@ func main() {
 		slog.String("service.version", version.Version),
 		slog.String("service.git_version", version.Commit),
 		slog.String("service.build_time", version.BuildTime),
-		slog.Bool("service.gracefull_shutdown", isProduction),
+		slog.Int64("service.shutdown_drain_ms", cfg.ShutdownDrainMs),
 	)
 
 	// Initialize telemetry
-	shutdown, _ := telemetry.Instance(globalCtx)
-	defer shutdown()
+	_, _ = telemetry.Instance(globalCtx)
+	app.Append(lifecycle.Hook{Name: "telemetry", OnStop: telemetry.Shutdown})
 
 	// Connect to MongoDB
 	db, err := database.Instance(globalCtx, cfg.MongoURI, cfg.MongoDBName)
@@ -67,112 +73,231 @@ func main() {
 		)
 		os.Exit(1)
 	}
+	app.Append(lifecycle.Hook{Name: "mongo", OnStop: db.Close})
 
 	// Wiring
-	productRepo := repository.NewProductRepository(db.Database)
+	productRepo := repository.NewProductRepository(db)
 	productService := service.NewProductService(productRepo)
-	productHandler := handler.NewProductHandler(productService)
 	externalHandler := handler.NewExternalHandler(cfg.ExternalHTTP)
 
-	// Wiring health service
-	healthService := service.NewHealthService(db.Client)
-	healthHandler := handler.NewHealthHandler(healthService)
+	// Wiring attachments
+	blobStore, err := storage.NewBlobStore(cfg, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to initialize attachment store",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	productService.SetBlobStore(blobStore)
+	attachmentService := service.NewAttachmentService(productRepo, blobStore, service.NewAttachmentConfig(cfg))
+	attachmentService.SetProductGuard(productService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
 
-	// Routing
-	mux := http.NewServeMux()
+	// Wiring report service
+	reportRepo := repository.NewReportRepository(db)
+	reportService := service.NewReportService(reportRepo,
+		time.Duration(cfg.ReportCacheTTLSec)*time.Second,
+		time.Duration(cfg.ReportTimeoutMs)*time.Millisecond,
+	)
+	reportService.SetProductGuard(productService)
 
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
-		}
-		resp := map[string]string{"data": "hello-world"}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(resp)
-	})
+	// Wiring price schedules
+	priceRepo, err := repository.NewPriceRepository(globalCtx, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to prepare price collections",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	productService.SetPriceHistory(priceService)
+	priceService.SetProductGuard(productService)
+	if cfg.PriceSchedulerEnabled {
+		app.Go("price-scheduler", worker.NewPriceScheduler(priceRepo, productRepo, cfg).Run)
+	}
 
-	mux.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
-		productHandler.GetAll(w, r)
-	})
+	// Wiring webhooks
+	webhookRepo, err := repository.NewWebhookRepository(globalCtx, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to prepare webhook collections",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	webhookService := service.NewWebhookService(webhookRepo)
+	productService.SetEventPublisher(webhookService)
+	if cfg.WebhookWorkerEnabled {
+		app.Go("webhook-worker", worker.NewWebhookWorker(webhookRepo, worker.NewWebhookWorkerConfig(cfg)).Run)
+	}
 
-	mux.HandleFunc("/product", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			productHandler.GetByID(w, r)
-		case http.MethodPost:
-			productHandler.Create(w, r)
-		case http.MethodPut:
-			productHandler.Update(w, r)
-		case http.MethodDelete:
-			productHandler.Delete(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Wiring the role policy: product actions are authorized in the
+	// service, so HTTP and gRPC enforce the same rules.
+	if cfg.PolicyFile != "" {
+		policyStore, err := policy.NewStore(cfg.PolicyFile)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to load policy",
+				slog.String("exception.message", err.Error()),
+				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+				slog.String("exception.stacktrace", string(debug.Stack())),
+			)
+			os.Exit(1)
 		}
-	})
+		productService.SetAuthorizer(policyStore)
+		app.Go("policy-watch", func(ctx context.Context) {
+			policyStore.Watch(ctx, time.Duration(cfg.PolicyReloadIntervalMs)*time.Millisecond)
+		})
+	}
 
-	mux.HandleFunc("/external", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			externalHandler.Fetch(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	// Wiring API keys
+	apiKeyRepo, err := repository.NewAPIKeyRepository(globalCtx, db)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to prepare API key collection",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
 
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			healthHandler.Check(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	// Wiring health checks: readiness follows the lifecycle, so it turns
+	// false for the shutdown drain.
+	healthService := service.NewHealthService(health.NewRegistryFromConfig(cfg, db, app))
+	healthHandler := handler.NewHealthHandler(healthService)
 
-	// HTTP server
-	wrappedMux := Chain(
-		mux,
-		middleware_http.TraceMiddleware(globalCtx),
-	)
-	server := &http.Server{
-		Addr:         ":" + cfg.AppPort,
-		Handler:      wrappedMux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+	// Wiring the gateway: the JSON routes bound in product.proto are
+	// transcoded to the same handlers the gRPC server uses.
+	gateway, err := handler.NewGateway(globalCtx, handler.GatewayServices{
+		Product: grpcHandler.NewProductGRPCHandler(productService),
+		Report:  grpcHandler.NewReportGRPCHandler(reportService),
+		Webhook: grpcHandler.NewWebhookGRPCHandler(webhookService),
+		Price:   grpcHandler.NewPriceGRPCHandler(priceService),
+		APIKey:  grpcHandler.NewAPIKeyGRPCHandler(apiKeyService),
+	}, cfg.HTTPMaxBodyBytes)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to initialize HTTP gateway",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+
+	// Wiring API docs
+	spec := handler.OpenAPI()
+	docsHandler, err := handler.NewDocsHandler(spec)
+	if err != nil {
+		logger.Error(globalCtx, "Failed to build OpenAPI document",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
 	}
 
-	go func() {
-		logger.Info(globalCtx, "HTTP server running", slog.String("data.addr", server.Addr))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error(globalCtx, "Server error",
+	// Routing
+	routes := handler.Routes(handler.Handlers{
+		Gateway:    gateway,
+		Attachment: attachmentHandler,
+		External:   externalHandler,
+		Health:     healthHandler,
+		Docs:       docsHandler,
+		Metrics:    metrics.Handler(),
+	})
+	// Refuse to start with routes the spec does not describe (or the other
+	// way round), so the published contract cannot silently go stale.
+	if err := handler.CheckSpec(spec, routes); err != nil {
+		logger.Error(globalCtx, "OpenAPI document does not match routes",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			slog.String("exception.stacktrace", string(debug.Stack())),
+		)
+		os.Exit(1)
+	}
+	// Authentication: AuthMiddleware verifies credentials ahead of tracing
+	// so the request log carries the subject; every route but the public
+	// ones then requires a principal with the route's scope.
+	middlewares := []func(http.Handler) http.Handler{}
+	// Compression wraps everything else, so the request log records
+	// response bodies uncompressed.
+	if cfg.CompressionEnabled {
+		middlewares = append(middlewares, middleware_http.CompressMiddleware(int(cfg.CompressionMinBytes)))
+	}
+	if cfg.AuthEnabled {
+		authenticator, err := service.NewAuthenticator(cfg, apiKeyService)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to initialize authentication",
 				slog.String("exception.message", err.Error()),
 				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
 				slog.String("exception.stacktrace", string(debug.Stack())),
 			)
 			os.Exit(1)
 		}
-	}()
-
-	// Handle shutdown based on environment
-	if !isProduction {
-		// For local development: skip graceful shutdown
-		logger.Info(globalCtx, "Running in local mode - graceful shutdown disabled")
-		<-globalCtx.Done() // wait for interrupt
-		logger.Info(globalCtx, "Received shutdown signal, exiting immediately")
-		os.Exit(0)
-	} else {
-		// For production/staging: use graceful shutdown
-		<-globalCtx.Done() // wait for interrupt
-		logger.Info(globalCtx, "Shutting down server")
-
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer shutdownCancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			logger.Error(globalCtx, "Graceful shutdown failed",
+		routes = handler.RequireAuth(routes)
+		middlewares = append(middlewares, middleware_http.AuthMiddleware(authenticator))
+	}
+	mux := http.NewServeMux()
+	handler.Register(mux, routes)
+
+	// HTTP server
+	middlewares = append(middlewares, middleware_http.TraceMiddleware(globalCtx, mux))
+	// Rate limiting and load shedding sit inside tracing so 429s and 503s
+	// are logged, and inside authentication so clients are keyed by
+	// principal; rate-limited requests never take a concurrency slot.
+	if cfg.RateLimitEnabled {
+		limiter, err := ratelimit.NewLimiterFromConfig(globalCtx, cfg, db)
+		if err != nil {
+			logger.Error(globalCtx, "Failed to initialize rate limiting",
 				slog.String("exception.message", err.Error()),
 				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
 				slog.String("exception.stacktrace", string(debug.Stack())),
 			)
-		} else {
-			logger.Info(globalCtx, "Server exited properly")
+			os.Exit(1)
 		}
+		middlewares = append(middlewares, middleware_http.RateLimitMiddleware(limiter, mux, handler.Priorities(routes)))
+	}
+	if cfg.LoadShedEnabled {
+		limiter := loadshed.NewLimiter(loadshed.NewConfig(cfg))
+		middlewares = append(middlewares, middleware_http.LoadShedMiddleware(limiter, mux, handler.Priorities(routes)))
+	}
+	middlewares = append(middlewares,
+		middleware_http.RecoverMiddleware(),
+		middleware_http.MongoSessionMiddleware(db),
+	)
+	wrappedMux := Chain(mux, middlewares...)
+	server := &http.Server{
+		Addr:         ":" + cfg.AppPort,
+		Handler:      wrappedMux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	app.Append(lifecycle.Hook{
+		Name: "http-server",
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Info(globalCtx, "HTTP server running", slog.String("data.addr", server.Addr))
+				if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
+					app.Fail(err)
+				}
+			}()
+			return nil
+		},
+		// Shutdown closes the listener and waits for in-flight requests.
+		OnStop: server.Shutdown,
+	})
+
+	if err := app.Run(globalCtx); err != nil {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/client"
+	"simple-crud/internal/compression"
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/repository"
+	"simple-crud/internal/seed"
+	"simple-crud/internal/telemetry"
+	"simple-crud/internal/version"
+)
+
+var (
+	count       = flag.Int("n", 1000, "number of products to generate")
+	seedValue   = flag.Int64("seed", 1, "generator seed; the same seed always yields the same catalog")
+	mode        = flag.String("mode", "repo", "write path: repo (direct to Mongo), http or grpc")
+	target      = flag.String("target", "", "server address for http/grpc modes (default EXTERNAL_HTTP / EXTERNAL_GRPC)")
+	concurrency = flag.Int("concurrency", 8, "number of concurrent writers")
+	wipe        = flag.Bool("wipe", false, "delete all existing products before seeding")
+	progress    = flag.Duration("progress", time.Second, "progress report interval (0 disables)")
+)
+
+func fail(ctx context.Context, msg string, err error) {
+	logger.Error(ctx, msg,
+		slog.String("exception.message", err.Error()),
+		slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+		slog.String("exception.stacktrace", string(debug.Stack())),
+	)
+	os.Exit(1)
+}
+
+func main() {
+	flag.Parse()
+
+	// Create cancellable context so Ctrl-C stops the run cleanly
+	bgCtx := context.Background()
+	globalCtx, stop := signal.NotifyContext(bgCtx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Instance()
+	cfg := config.Instance()
+
+	logger.Info(
+		globalCtx,
+		"Starting seed",
+		slog.String("service.name", cfg.AppName),
+		slog.String("service.version", version.Version),
+		slog.String("service.git_version", version.Commit),
+		slog.String("service.build_time", version.BuildTime),
+		slog.String("data.mode", *mode),
+		slog.Int("data.count", *count),
+		slog.Int64("data.seed", *seedValue),
+		slog.Int("data.concurrency", *concurrency),
+		slog.Bool("data.wipe", *wipe),
+	)
+
+	shutdown, _ := telemetry.Instance(globalCtx)
+	defer shutdown()
+
+	var sink seed.Sink
+	switch *mode {
+	case "repo":
+		db, err := database.Instance(globalCtx, cfg.MongoURI, cfg.MongoDBName)
+		if err != nil {
+			fail(globalCtx, "Failed to connect to MongoDB", err)
+		}
+		sink = seed.NewRepositorySink(repository.NewProductRepository(db))
+	case "http":
+		addr := *target
+		if addr == "" {
+			addr = cfg.ExternalHTTP
+		}
+		httpClient := client.NewHTTPClient(addr, 10*time.Second)
+		if cfg.ClientAPIKey != "" {
+			httpClient.SetDefaultHeader("X-API-Key", cfg.ClientAPIKey)
+		}
+		sink = seed.NewHTTPSink(httpClient)
+	case "grpc":
+		addr := *target
+		if addr == "" {
+			addr = cfg.ExternalGRPC
+		}
+		opts := []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+		}
+		if cfg.ClientAPIKey != "" {
+			opts = append(opts, grpc.WithPerRPCCredentials(auth.APIKeyCredentials(cfg.ClientAPIKey)))
+		}
+		compressor, err := compression.DialOption(cfg.GRPCCompression)
+		if err != nil {
+			fail(globalCtx, "Invalid GRPC_COMPRESSION", err)
+		}
+		opts = append(opts, compressor)
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			fail(globalCtx, "Failed to connect to gRPC server", err)
+		}
+		defer conn.Close()
+		sink = seed.NewGRPCSink(pb.NewProductServiceClient(conn))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -mode %q (want repo, http or grpc)\n", *mode)
+		os.Exit(2)
+	}
+
+	if *wipe {
+		n, err := sink.Wipe(globalCtx)
+		if err != nil {
+			fail(globalCtx, "Failed to wipe products", err)
+		}
+		logger.Info(globalCtx, "Wiped products", slog.Int("data.count", n))
+	}
+
+	result := seed.Run(globalCtx, seed.NewGenerator(*seedValue), sink, seed.Options{
+		Count:       *count,
+		Concurrency: *concurrency,
+		ReportEvery: *progress,
+		OnProgress: func(p seed.Progress) {
+			logger.Info(globalCtx, "Seed progress",
+				slog.Int64("data.done", p.Done),
+				slog.Int64("data.failed", p.Failed),
+				slog.Int64("data.total", p.Total),
+				slog.Float64("data.rate_per_sec", p.Rate()),
+			)
+		},
+		OnError: func(i int, err error) {
+			logger.Error(globalCtx, "Failed to write product",
+				slog.Int("data.index", i),
+				slog.String("exception.message", err.Error()),
+			)
+		},
+	})
+
+	logger.Info(globalCtx, "Seed finished",
+		slog.Int64("data.done", result.Done),
+		slog.Int64("data.failed", result.Failed),
+		slog.Int64("data.elapsed_ms", result.Elapsed.Milliseconds()),
+	)
+	if result.Failed > 0 || globalCtx.Err() != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Principal kinds.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// APIKeyVerifier resolves an API key to its principal. Rejections wrap
+// ErrInvalidToken.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// Authenticator accepts either credential: a bearer JWT in Authorization or
+// an API key in X-API-Key (gRPC: x-api-key). Either may be nil when that
+// kind of credential is not configured.
+type Authenticator struct {
+	Tokens  *Verifier
+	APIKeys APIKeyVerifier
+}
+
+// Authenticate returns ctx carrying the principal of the credentials, the
+// reason they were rejected, or nothing when there were none. An API key
+// takes precedence when both are sent.
+func (a *Authenticator) Authenticate(ctx context.Context, authorization, apiKey string) context.Context {
+	var (
+		p   *Principal
+		err error
+	)
+	switch {
+	case apiKey != "":
+		if a.APIKeys == nil {
+			err = errors.Join(ErrInvalidToken, errors.New("API keys are not accepted"))
+			break
+		}
+		p, err = a.APIKeys.VerifyAPIKey(ctx, apiKey)
+	case authorization != "":
+		token, ok := BearerToken(authorization)
+		switch {
+		case !ok:
+			err = ErrInvalidToken
+		case a.Tokens == nil:
+			err = errors.Join(ErrInvalidToken, errors.New("bearer tokens are not accepted"))
+		default:
+			p, err = a.Tokens.Verify(ctx, token)
+		}
+	default:
+		return ctx
+	}
+	if err != nil {
+		return WithError(ctx, err)
+	}
+	return WithPrincipal(ctx, p)
+}
+
+// APIKeyCredentials sends an API key with every gRPC call. It does not
+// require transport security, matching the plaintext connections the
+// clients use.
+type APIKeyCredentials string
+
+func (k APIKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"x-api-key": string(k)}, nil
+}
+
+func (k APIKeyCredentials) RequireTransportSecurity() bool {
+	return false
+}
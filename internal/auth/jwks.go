@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxJWKSSize bounds the key set document.
+const maxJWKSSize = 1 << 20
+
+// JWKS is a JSON Web Key Set read from a file or an http(s) URL. Keys are
+// cached for the refresh interval; a token signed with an unknown key ID
+// triggers an early refetch (at most once per minRefetch) so rotated keys
+// are picked up without waiting. Fetches run in the background, one at a
+// time, and only requests that need a key the cache lacks wait for them;
+// when a refetch fails the cached keys stay in use.
+type JWKS struct {
+	source     string
+	refresh    time.Duration
+	minRefetch time.Duration
+	client     *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+	triedAt   time.Time
+	// fetching is closed when the fetch in flight ends; nil when there is
+	// none. fetchErr is the outcome of the last fetch.
+	fetching chan struct{}
+	fetchErr error
+}
+
+// NewJWKS reads keys from source: a file path, a file:// URL or an http(s)
+// URL.
+func NewJWKS(source string, refresh time.Duration) *JWKS {
+	minRefetch := 30 * time.Second
+	if refresh < minRefetch {
+		minRefetch = refresh
+	}
+	return &JWKS{
+		source:     source,
+		refresh:    refresh,
+		minRefetch: minRefetch,
+		client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Key returns the public (or, for "oct" keys, secret) key with the given ID.
+// An empty kid matches when the set holds exactly one key.
+func (s *JWKS) Key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+	now := time.Now()
+	stale := s.keys == nil || now.Sub(s.fetchedAt) >= s.refresh
+	key, known := s.lookup(kid)
+	if (stale || !known) && s.fetching == nil && now.Sub(s.triedAt) >= s.minRefetch {
+		s.triedAt = now
+		s.fetching = make(chan struct{})
+		go s.fetch(context.WithoutCancel(ctx), s.fetching)
+	}
+	wait := s.fetching
+	s.mu.Unlock()
+
+	if known {
+		return key, nil
+	}
+	if wait != nil {
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, known = s.lookup(kid)
+	if known {
+		return key, nil
+	}
+	if s.keys == nil && s.fetchErr != nil {
+		return nil, s.fetchErr
+	}
+	return nil, fmt.Errorf("no key with id %q", kid)
+}
+
+func (s *JWKS) lookup(kid string) (any, bool) {
+	if kid == "" {
+		if len(s.keys) != 1 {
+			return nil, false
+		}
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// fetch reloads the keys and closes done. A failure keeps the cached keys.
+func (s *JWKS) fetch(ctx context.Context, done chan struct{}) {
+	keys, err := s.load(ctx)
+
+	s.mu.Lock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+	s.fetchErr = err
+	s.fetching = nil
+	s.mu.Unlock()
+	close(done)
+
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	span.AddEvent("jwks.refreshed", trace.WithAttributes(attribute.Int("jwks.keys", len(keys))))
+}
+
+func (s *JWKS) load(ctx context.Context) (map[string]any, error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load JWKS: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *JWKS) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(strings.TrimPrefix(s.source, "file://"))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: HTTP %d", s.source, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// parseJWKS decodes the signing keys of a key set. Keys of other types or
+// uses are skipped rather than failing the whole set.
+func parseJWKS(data []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.key()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) key() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64Int(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64Int(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := b64Int(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64Int(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !pub.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on P-256")
+		}
+		return pub, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, nil
+	}
+}
+
+func b64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
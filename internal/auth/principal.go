@@ -0,0 +1,73 @@
+// Package auth authenticates callers from JWT bearer tokens and carries the
+// resulting principal in the request context. It depends on neither config
+// nor logger, so both (and every transport) can use it.
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrMissingToken means the request carried no credentials.
+	ErrMissingToken = errors.New("missing credentials")
+	// ErrInvalidToken wraps every reason a token or API key was rejected.
+	ErrInvalidToken = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	// Method is how the caller authenticated: MethodJWT or MethodAPIKey.
+	Method    string
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	// Scopes come from the "scope" (space-separated) or "scp" claim, or
+	// from the API key.
+	Scopes []string
+	// Roles come from the "roles" claim; API keys have none. The policy
+	// engine may grant more from the subject or the scopes.
+	Roles []string
+	// Claims holds every claim of a JWT; nil for API keys.
+	Claims map[string]any
+}
+
+type principalKey struct{}
+
+type errorKey struct{}
+
+// WithPrincipal returns ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the request, if it authenticated.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Subject returns the subject of the principal in ctx, or "".
+func Subject(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// WithError records why authentication failed, for the layer that decides
+// whether the request may go on without a principal.
+func WithError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, errorKey{}, err)
+}
+
+// ErrorFromContext returns the recorded authentication failure, or
+// ErrMissingToken when the request carried no token at all.
+func ErrorFromContext(ctx context.Context) error {
+	if err, ok := ctx.Value(errorKey{}).(error); ok && err != nil {
+		return err
+	}
+	return ErrMissingToken
+}
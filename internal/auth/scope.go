@@ -0,0 +1,24 @@
+package auth
+
+import "slices"
+
+// Scopes limit what a credential may do, whatever the caller's identity.
+// JWTs carry them in "scope"/"scp"; API keys are issued with them.
+const (
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+	// ScopeAdmin covers webhooks and API key management.
+	ScopeAdmin = "admin"
+)
+
+// KnownScopes lists every scope a credential may be issued with.
+var KnownScopes = []string{ScopeProductsRead, ScopeProductsWrite, ScopeAdmin}
+
+// HasScope reports whether p was granted scope. products:write implies
+// products:read.
+func (p *Principal) HasScope(scope string) bool {
+	if slices.Contains(p.Scopes, scope) {
+		return true
+	}
+	return scope == ScopeProductsRead && slices.Contains(p.Scopes, ScopeProductsWrite)
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Config configures a Verifier. At least one of HS256Secret and JWKS must be
+// set; Issuer and Audience are only checked when set.
+type Config struct {
+	Issuer      string
+	Audience    string
+	HS256Secret []byte
+	// JWKS is a file path or http(s) URL of a JSON Web Key Set.
+	JWKS        string
+	JWKSRefresh time.Duration
+	Leeway      time.Duration
+}
+
+// Verifier validates bearer tokens signed with HS256, RS256 or ES256.
+// Expiry is required; the key must match the algorithm, so an RSA public key
+// can never be used as an HMAC secret.
+type Verifier struct {
+	secret []byte
+	jwks   *JWKS
+	parser *jwt.Parser
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if len(cfg.HS256Secret) == 0 && cfg.JWKS == "" {
+		return nil, errors.New("auth: an HS256 secret or a JWKS source is required")
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256", "ES256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v := &Verifier{secret: cfg.HS256Secret, parser: jwt.NewParser(opts...)}
+	if cfg.JWKS != "" {
+		refresh := cfg.JWKSRefresh
+		if refresh <= 0 {
+			refresh = 5 * time.Minute
+		}
+		v.jwks = NewJWKS(cfg.JWKS, refresh)
+	}
+	return v, nil
+}
+
+// Verify validates raw and returns its principal. Every rejection wraps
+// ErrInvalidToken.
+func (v *Verifier) Verify(ctx context.Context, raw string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(raw, claims, v.keyFunc(ctx)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	p := &Principal{Method: MethodJWT, Claims: claims}
+	p.Subject, _ = claims.GetSubject()
+	p.Issuer, _ = claims.GetIssuer()
+	p.Audience, _ = claims.GetAudience()
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		p.ExpiresAt = exp.Time
+	}
+	if p.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidToken)
+	}
+	p.Scopes = scopes(claims)
+	p.Roles = stringList(claims["roles"])
+	return p, nil
+}
+
+func (v *Verifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		alg := t.Method.Alg()
+
+		if alg == "HS256" && kid == "" && len(v.secret) > 0 {
+			return v.secret, nil
+		}
+		if v.jwks == nil {
+			return nil, fmt.Errorf("no key for %s", alg)
+		}
+		key, err := v.jwks.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if !keyFits(alg, key) {
+			return nil, fmt.Errorf("key %q cannot verify %s", kid, alg)
+		}
+		return key, nil
+	}
+}
+
+func keyFits(alg string, key any) bool {
+	switch k := key.(type) {
+	case []byte:
+		return alg == "HS256"
+	case *rsa.PublicKey:
+		return alg == "RS256"
+	case *ecdsa.PublicKey:
+		return alg == "ES256" && k.Curve == elliptic.P256()
+	default:
+		return false
+	}
+}
+
+func scopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return stringList(claims["scp"])
+}
+
+// stringList reads a claim that is either a space-separated string or an
+// array of strings.
+func stringList(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// BearerToken extracts the token from an Authorization header value.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
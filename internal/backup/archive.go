@@ -0,0 +1,387 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/version"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var BackupTracer = otel.Tracer("Backup")
+
+// Archive layout: a gzip-compressed tar whose first entry is manifest.json,
+// followed by one NDJSON file per collection.
+const (
+	ManifestName  = "manifest.json"
+	FormatName    = "simple-crud-backup"
+	FormatVersion = 1
+
+	importBatchSize = 500
+	maxRecordSize   = 16 << 20
+)
+
+var ErrCorruptArchive = errors.New("corrupt backup archive")
+
+type Manifest struct {
+	Format      string          `json:"format"`
+	Version     int             `json:"version"`
+	CreatedAt   time.Time       `json:"created_at"`
+	AppVersion  string          `json:"app_version"`
+	Collections []CollectionRef `json:"collections"`
+}
+
+type CollectionRef struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Format string `json:"format"`
+	Count  int64  `json:"count"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// Collection returns the entry for name, or nil.
+func (m *Manifest) Collection(name string) *CollectionRef {
+	for i := range m.Collections {
+		if m.Collections[i].Name == name {
+			return &m.Collections[i]
+		}
+	}
+	return nil
+}
+
+// Create writes an archive of collections to w. Collections are staged in a
+// temporary directory because tar needs each file's size up front and the
+// manifest, which carries the checksums, has to come first.
+func Create(ctx context.Context, w io.Writer, collections []Collection) (*Manifest, error) {
+	ctx, span := BackupTracer.Start(ctx, "Backup.Create")
+	defer span.End()
+
+	dir, err := os.MkdirTemp("", "simple-crud-backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &Manifest{
+		Format:     FormatName,
+		Version:    FormatVersion,
+		CreatedAt:  time.Now().UTC(),
+		AppVersion: version.Version,
+	}
+	for _, c := range collections {
+		ref, err := exportCollection(ctx, dir, c)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", c.Name(), err)
+		}
+		manifest.Collections = append(manifest.Collections, *ref)
+		logger.Info(ctx, "Exported collection",
+			slog.String("data.collection", ref.Name),
+			slog.Int64("data.count", ref.Count),
+			slog.Int64("data.bytes", ref.Bytes),
+		)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, ManifestName, manifest.CreatedAt, int64(len(manifestJSON)), bytes.NewReader(manifestJSON)); err != nil {
+		return nil, err
+	}
+	for _, ref := range manifest.Collections {
+		f, err := os.Open(filepath.Join(dir, ref.File))
+		if err != nil {
+			return nil, err
+		}
+		err = writeEntry(tw, ref.File, manifest.CreatedAt, ref.Bytes, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func exportCollection(ctx context.Context, dir string, c Collection) (*CollectionRef, error) {
+	ctx, span := BackupTracer.Start(ctx, "Backup.Export")
+	defer span.End()
+	span.SetAttributes(attribute.String("backup.collection", c.Name()))
+
+	ref := &CollectionRef{Name: c.Name(), File: c.Name() + ".ndjson", Format: c.Format()}
+	f, err := os.Create(filepath.Join(dir, ref.File))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(f, h))
+	err = c.Export(ctx, func(record []byte) error {
+		if _, err := bw.Write(record); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+		ref.Count++
+		ref.Bytes += int64(len(record)) + 1
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	ref.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return ref, f.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, size int64, r io.Reader) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: modTime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// archiveReader walks the entries of an archive, starting after the manifest.
+type archiveReader struct {
+	gz       *gzip.Reader
+	tr       *tar.Reader
+	manifest *Manifest
+}
+
+func openArchive(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptArchive, err)
+	}
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptArchive, err)
+	}
+	if hdr.Name != ManifestName {
+		return nil, fmt.Errorf("%w: first entry is %q, want %s", ErrCorruptArchive, hdr.Name, ManifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(tr, maxRecordSize)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", ErrCorruptArchive, err)
+	}
+	if m.Format != FormatName {
+		return nil, fmt.Errorf("%w: format %q, want %q", ErrCorruptArchive, m.Format, FormatName)
+	}
+	if m.Version < 1 || m.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d (this build reads up to %d)", m.Version, FormatVersion)
+	}
+	return &archiveReader{gz: gz, tr: tr, manifest: &m}, nil
+}
+
+// next returns the manifest entry and content of the next collection file,
+// or io.EOF.
+func (a *archiveReader) next() (*CollectionRef, io.Reader, error) {
+	hdr, err := a.tr.Next()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, io.EOF
+		}
+		return nil, nil, fmt.Errorf("%w: %v", ErrCorruptArchive, err)
+	}
+	for i := range a.manifest.Collections {
+		if a.manifest.Collections[i].File == hdr.Name {
+			return &a.manifest.Collections[i], a.tr, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: entry %q is not in the manifest", ErrCorruptArchive, hdr.Name)
+}
+
+// Verify checks every collection file against the manifest's size, record
+// count and checksum, and that no file is missing.
+func Verify(ctx context.Context, r io.Reader) (*Manifest, error) {
+	ctx, span := BackupTracer.Start(ctx, "Backup.Verify")
+	defer span.End()
+
+	a, err := openArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for {
+		ref, content, err := a.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		counter := &lineCounter{}
+		n, err := io.Copy(io.MultiWriter(h, counter), content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrCorruptArchive, ref.File, err)
+		}
+		switch {
+		case n != ref.Bytes:
+			return nil, fmt.Errorf("%w: %s has %d bytes, manifest says %d", ErrCorruptArchive, ref.File, n, ref.Bytes)
+		case counter.lines != ref.Count:
+			return nil, fmt.Errorf("%w: %s has %d records, manifest says %d", ErrCorruptArchive, ref.File, counter.lines, ref.Count)
+		case hex.EncodeToString(h.Sum(nil)) != ref.SHA256:
+			return nil, fmt.Errorf("%w: %s checksum mismatch", ErrCorruptArchive, ref.File)
+		}
+		seen[ref.File] = true
+	}
+	for _, ref := range a.manifest.Collections {
+		if !seen[ref.File] {
+			return nil, fmt.Errorf("%w: %s is missing", ErrCorruptArchive, ref.File)
+		}
+	}
+	logger.Info(ctx, "Backup verified", slog.Int("data.collections", len(a.manifest.Collections)))
+	return a.manifest, nil
+}
+
+type lineCounter struct{ lines int64 }
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.lines += int64(bytes.Count(p, []byte{'\n'}))
+	return len(p), nil
+}
+
+// Restore verifies the whole archive first, so a damaged archive never
+// clears or half-loads a collection, then imports the collections that have
+// a handler in collections. Archived collections without a handler are an
+// error; pass only the wanted ones in `only` to restore a subset.
+func Restore(ctx context.Context, rs io.ReadSeeker, collections []Collection, mode Mode, only []string) (*Manifest, error) {
+	ctx, span := BackupTracer.Start(ctx, "Backup.Restore")
+	defer span.End()
+	span.SetAttributes(attribute.String("backup.mode", string(mode)))
+
+	manifest, err := Verify(ctx, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := map[string]Collection{}
+	for _, c := range collections {
+		handlers[c.Name()] = c
+	}
+	wanted := map[string]bool{}
+	for _, ref := range manifest.Collections {
+		if len(only) > 0 && !contains(only, ref.Name) {
+			continue
+		}
+		c, ok := handlers[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("no handler for archived collection %q", ref.Name)
+		}
+		if c.Format() != ref.Format {
+			return nil, fmt.Errorf("collection %q is archived as %s, handler reads %s", ref.Name, ref.Format, c.Format())
+		}
+		wanted[ref.Name] = true
+	}
+	for _, name := range only {
+		if manifest.Collection(name) == nil {
+			return nil, fmt.Errorf("collection %q is not in the archive", name)
+		}
+	}
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	a, err := openArchive(rs)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		ref, content, err := a.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !wanted[ref.Name] {
+			continue
+		}
+		if err := importCollection(ctx, handlers[ref.Name], content, mode); err != nil {
+			return nil, fmt.Errorf("import %s: %w", ref.Name, err)
+		}
+		logger.Info(ctx, "Restored collection",
+			slog.String("data.collection", ref.Name),
+			slog.Int64("data.count", ref.Count),
+			slog.String("data.mode", string(mode)),
+		)
+	}
+	return manifest, nil
+}
+
+func importCollection(ctx context.Context, c Collection, r io.Reader, mode Mode) error {
+	ctx, span := BackupTracer.Start(ctx, "Backup.Import")
+	defer span.End()
+	span.SetAttributes(attribute.String("backup.collection", c.Name()))
+
+	if mode == ModeReplace {
+		if err := c.Clear(ctx); err != nil {
+			return err
+		}
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), maxRecordSize)
+	batch := make([][]byte, 0, importBatchSize)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		batch = append(batch, bytes.Clone(sc.Bytes()))
+		if len(batch) == importBatchSize {
+			if err := c.Import(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return c.Import(ctx, batch)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"simple-crud/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mode selects how a restore treats existing data.
+type Mode string
+
+const (
+	// ModeMerge upserts archived records by ID and keeps everything else.
+	ModeMerge Mode = "merge"
+	// ModeReplace clears each restored collection first.
+	ModeReplace Mode = "replace"
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeMerge, ModeReplace:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown restore mode %q (want merge or replace)", s)
+	}
+}
+
+// Collection is one named set of records in an archive. Each record is a
+// single line of JSON.
+type Collection interface {
+	Name() string
+	// Format describes the record encoding, e.g. "json" or "extjson".
+	Format() string
+	Export(ctx context.Context, fn func(record []byte) error) error
+	// Import upserts records by ID.
+	Import(ctx context.Context, records [][]byte) error
+	Clear(ctx context.Context) error
+}
+
+// ProductStore is what the product collection needs from a repository. Any
+// backend implementing it can be backed up and restored, which is how data
+// moves between backends.
+type ProductStore interface {
+	Stream(ctx context.Context, batchSize int, fn func([]model.Product) error) error
+	Upsert(ctx context.Context, p *model.Product) error
+	DeleteAll(ctx context.Context) (int64, error)
+}
+
+// ProductCollection archives products as plain JSON of model.Product, so the
+// records do not depend on the storage backend.
+type ProductCollection struct {
+	store ProductStore
+}
+
+func NewProductCollection(store ProductStore) *ProductCollection {
+	return &ProductCollection{store: store}
+}
+
+func (c *ProductCollection) Name() string   { return "product" }
+func (c *ProductCollection) Format() string { return "json" }
+
+func (c *ProductCollection) Export(ctx context.Context, fn func([]byte) error) error {
+	return c.store.Stream(ctx, 500, func(products []model.Product) error {
+		for i := range products {
+			b, err := json.Marshal(&products[i])
+			if err != nil {
+				return err
+			}
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *ProductCollection) Import(ctx context.Context, records [][]byte) error {
+	for _, rec := range records {
+		var p model.Product
+		if err := json.Unmarshal(rec, &p); err != nil {
+			return err
+		}
+		if p.ID.IsZero() {
+			return errors.New("product record without id")
+		}
+		if err := c.store.Upsert(ctx, &p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ProductCollection) Clear(ctx context.Context) error {
+	_, err := c.store.DeleteAll(ctx)
+	return err
+}
+
+// MongoCollection archives a Mongo collection verbatim as canonical Extended
+// JSON, which keeps BSON types (ObjectIDs, dates, binary) intact. It is used
+// for auxiliary collections that have no backend-neutral model.
+type MongoCollection struct {
+	coll *mongo.Collection
+}
+
+func NewMongoCollection(db *mongo.Database, name string) *MongoCollection {
+	return &MongoCollection{coll: db.Collection(name)}
+}
+
+func (c *MongoCollection) Name() string   { return c.coll.Name() }
+func (c *MongoCollection) Format() string { return "extjson" }
+
+func (c *MongoCollection) Export(ctx context.Context, fn func([]byte) error) error {
+	cursor, err := c.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		b, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (c *MongoCollection) Import(ctx context.Context, records [][]byte) error {
+	models := make([]mongo.WriteModel, 0, len(records))
+	for _, rec := range records {
+		var doc bson.Raw
+		if err := bson.UnmarshalExtJSON(rec, true, &doc); err != nil {
+			return err
+		}
+		id, err := doc.LookupErr("_id")
+		if err != nil {
+			return fmt.Errorf("%s record without _id", c.Name())
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: id}}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := c.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (c *MongoCollection) Clear(ctx context.Context) error {
+	_, err := c.coll.DeleteMany(ctx, bson.M{})
+	return err
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// maxEntries bounds a TTL cache; when it is full, Set evicts the entry that
+// expires soonest.
+const maxEntries = 1024
+
+// TTL is a small in-memory cache whose entries expire after a fixed duration.
+// Expired entries are swept from Set at most once per duration, so keys that
+// are never read again do not pile up. It is safe for concurrent use.
+type TTL[V any] struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	items     map[string]entry[V]
+	nextSweep time.Time
+}
+
+func NewTTL[V any](ttl time.Duration) *TTL[V] {
+	return &TTL[V]{
+		ttl:   ttl,
+		items: make(map[string]entry[V]),
+	}
+}
+
+// TTL returns the configured time-to-live.
+func (c *TTL[V]) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *TTL[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		delete(c.items, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *TTL[V]) Set(key string, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.After(c.nextSweep) {
+		c.sweep(now)
+	}
+	if _, ok := c.items[key]; !ok && len(c.items) >= maxEntries {
+		c.evictOldest()
+	}
+	c.items[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *TTL[V]) sweep(now time.Time) {
+	for k, e := range c.items {
+		if now.After(e.expiresAt) {
+			delete(c.items, k)
+		}
+	}
+	c.nextSweep = now.Add(c.ttl)
+}
+
+func (c *TTL[V]) evictOldest() {
+	var oldest string
+	var at time.Time
+	for k, e := range c.items {
+		if at.IsZero() || e.expiresAt.Before(at) {
+			oldest, at = k, e.expiresAt
+		}
+	}
+	delete(c.items, oldest)
+}
+
+// Purge drops every entry.
+func (c *TTL[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]entry[V])
+}
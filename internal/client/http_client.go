@@ -105,6 +105,7 @@ func (c *HTTPClient) Do(opts RequestOptions, result interface{}) error {
 	}
 
 	ctx, span := HttpClientTracer.Start(ctx, "backend-http-request")
+	defer span.End()
 
 	req, err := http.NewRequestWithContext(ctx, opts.Method, fullURL, bodyReader)
 	if err != nil {
@@ -0,0 +1,102 @@
+package compression
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+)
+
+// Importing this package registers the zstd and gzip gRPC compressors. A
+// server answers with the compressor its client used, and advertises the
+// registered ones in grpc-accept-encoding, so there is nothing to configure
+// server-side; clients choose theirs with DialOption.
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// DialOption makes a client compress its requests with name ("gzip" or
+// "zstd"); "" or "identity" leaves them uncompressed. Responses are
+// decompressed whatever the server picks.
+func DialOption(name string) (grpc.DialOption, error) {
+	if name == "" || name == "identity" {
+		return grpc.EmptyDialOption{}, nil
+	}
+	if encoding.GetCompressor(name) == nil {
+		return nil, fmt.Errorf("unknown gRPC compressor %q", name)
+	}
+	return grpc.WithDefaultCallOptions(grpc.UseCompressor(name)), nil
+}
+
+// zstdCompressor is the gRPC compressor for zstd, pooling its encoders and
+// decoders like the built-in gzip one.
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func (c *zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	enc, ok := c.encoders.Get().(*zstd.Encoder)
+	if !ok {
+		var err error
+		enc, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1), zstd.WithLowerEncoderMem(true))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		enc.Reset(w)
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w.Encoder)
+	return w.Encoder.Close()
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	dec, ok := c.decoders.Get().(*zstd.Decoder)
+	if !ok {
+		var err error
+		dec, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, err
+		}
+	} else if err := dec.Reset(r); err != nil {
+		c.decoders.Put(dec)
+		return nil, err
+	}
+	return &zstdReader{Decoder: dec, pool: &c.decoders}, nil
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+	done bool
+}
+
+// Read hands the decoder back to the pool once the message is consumed.
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.done = true
+		r.pool.Put(r.Decoder)
+	}
+	return n, err
+}
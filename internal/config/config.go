@@ -26,6 +26,117 @@ type Config struct {
 	RemoteLogHttpURI       string
 	RemoteTraceRpcURI      string
 	RemoteProfilingHttpURI string
+
+	// Read routing. The "list" variants apply to scan-style queries
+	// (FindAll, search) and fall back to the defaults when unset.
+	// MongoMaxStalenessSec is 0 (no bound) or at least 90, the smallest
+	// bound MongoDB accepts. With MongoCausalConsistency, local and
+	// available read concerns are raised to majority and writes use
+	// majority, without which sessions do not read their own writes.
+	MongoReadPreference     string
+	MongoReadConcern        string
+	MongoListReadPreference string
+	MongoListReadConcern    string
+	MongoMaxStalenessSec    int64
+	MongoCausalConsistency  bool
+
+	// Reporting: results are cached for ReportCacheTTLSec and each report
+	// is bounded by ReportTimeoutMs (or the caller's deadline if sooner).
+	ReportCacheTTLSec int64
+	ReportTimeoutMs   int64
+
+	// Outbound webhooks
+	WebhookWorkerEnabled  bool
+	WebhookPollIntervalMs int64
+	WebhookTimeoutMs      int64
+	WebhookMaxAttempts    int64
+	WebhookBackoffBaseMs  int64
+	WebhookBackoffMaxMs   int64
+
+	// Product attachments. AttachmentStore is "gridfs" or "local"; the
+	// allowed types are a comma-separated list matched against the sniffed
+	// (or, with sniffing off, the declared) content type.
+	AttachmentStore        string
+	AttachmentLocalDir     string
+	AttachmentMaxBytes     int64
+	AttachmentAllowedTypes string
+	AttachmentSniffContent bool
+
+	// Scheduled price changes
+	PriceSchedulerEnabled    bool
+	PriceSchedulerIntervalMs int64
+
+	// Authentication. API keys are always accepted; bearer JWTs are
+	// verified against AuthHS256Secret and/or the key set at AuthJWKS (a
+	// file path or URL) when either is set. AuthIssuer and AuthAudience are
+	// only checked when set.
+	AuthEnabled        bool
+	AuthIssuer         string
+	AuthAudience       string
+	AuthJWKS           string
+	AuthJWKSRefreshSec int64
+	AuthLeewaySec      int64
+	AuthHS256Secret    string
+
+	// Authorization: PolicyFile is a JSON role policy evaluated on every
+	// product action (unset: scopes alone decide). It is re-read when it
+	// changes, checked every PolicyReloadIntervalMs.
+	PolicyFile             string
+	PolicyReloadIntervalMs int64
+
+	// Rate limiting: token buckets per client (principal, else remote IP).
+	// Limits are "<count>/<s|m|h>[:<burst>]"; RateLimitRoutes overrides
+	// RateLimitDefault for comma-separated "<route>=<limit>" pairs, routes
+	// being HTTP patterns ("POST /products") or gRPC full method names.
+	// RateLimitStore is "memory" (per replica) or "mongo" (shared).
+	RateLimitEnabled bool
+	RateLimitDefault string
+	RateLimitRoutes  string
+	RateLimitStore   string
+
+	// Load shedding: an adaptive concurrency limit between LoadShedMinLimit
+	// and LoadShedMaxLimit, lowered while requests take longer than
+	// LoadShedLatencyTargetMs.
+	LoadShedEnabled         bool
+	LoadShedInitialLimit    int64
+	LoadShedMinLimit        int64
+	LoadShedMaxLimit        int64
+	LoadShedLatencyTargetMs int64
+
+	// Request size limits: HTTP bodies decoded by the gateway, and gRPC
+	// messages in each direction.
+	HTTPMaxBodyBytes    int64
+	GRPCMaxRecvMsgBytes int64
+	GRPCMaxSendMsgBytes int64
+
+	// Compression: HTTP responses of at least CompressionMinBytes are
+	// compressed as negotiated by Accept-Encoding; the gRPC clients compress
+	// requests with GRPCCompression (gzip, zstd or identity).
+	CompressionEnabled  bool
+	CompressionMinBytes int64
+	GRPCCompression     string
+
+	// Shutdown: on SIGINT/SIGTERM the servers keep serving, reported not
+	// ready, for ShutdownDrainMs; then each component gets up to
+	// ShutdownHookTimeoutMs to stop. A second signal exits at once.
+	ShutdownDrainMs       int64
+	ShutdownHookTimeoutMs int64
+
+	// Health checks: each dependency check gives up after
+	// HealthCheckTimeoutMs and its result is reused for HealthCheckCacheMs.
+	// The gRPC health service re-evaluates readiness every
+	// GRPCHealthIntervalMs.
+	HealthCheckTimeoutMs int64
+	HealthCheckCacheMs   int64
+	GRPCHealthIntervalMs int64
+
+	// Metrics: the HTTP server serves /metrics on its own port; the gRPC
+	// server serves it on MetricsPort.
+	MetricsPort string
+
+	// ClientAPIKey is sent as X-API-Key (gRPC: x-api-key) by the clients
+	// and by /external.
+	ClientAPIKey string
 }
 
 // SafeConfig adalah struct untuk logging yang aman (tanpa sensitive data)
@@ -40,6 +151,70 @@ type SafeConfig struct {
 	RemoteLogHttpURI       string `json:"remote_log_http_uri"`
 	RemoteTraceRpcURI      string `json:"remote_trace_rpc_uri"`
 	RemoteProfilingHttpURI string `json:"remote_profiling_http_uri"`
+
+	MongoReadPreference     string `json:"mongo_read_preference"`
+	MongoReadConcern        string `json:"mongo_read_concern"`
+	MongoListReadPreference string `json:"mongo_list_read_preference"`
+	MongoListReadConcern    string `json:"mongo_list_read_concern"`
+	MongoMaxStalenessSec    int64  `json:"mongo_max_staleness_sec"`
+	MongoCausalConsistency  bool   `json:"mongo_causal_consistency"`
+
+	ReportCacheTTLSec int64 `json:"report_cache_ttl_sec"`
+	ReportTimeoutMs   int64 `json:"report_timeout_ms"`
+
+	WebhookWorkerEnabled  bool  `json:"webhook_worker_enabled"`
+	WebhookPollIntervalMs int64 `json:"webhook_poll_interval_ms"`
+	WebhookTimeoutMs      int64 `json:"webhook_timeout_ms"`
+	WebhookMaxAttempts    int64 `json:"webhook_max_attempts"`
+	WebhookBackoffBaseMs  int64 `json:"webhook_backoff_base_ms"`
+	WebhookBackoffMaxMs   int64 `json:"webhook_backoff_max_ms"`
+
+	AttachmentStore        string `json:"attachment_store"`
+	AttachmentLocalDir     string `json:"attachment_local_dir"`
+	AttachmentMaxBytes     int64  `json:"attachment_max_bytes"`
+	AttachmentAllowedTypes string `json:"attachment_allowed_types"`
+	AttachmentSniffContent bool   `json:"attachment_sniff_content"`
+
+	PriceSchedulerEnabled    bool  `json:"price_scheduler_enabled"`
+	PriceSchedulerIntervalMs int64 `json:"price_scheduler_interval_ms"`
+
+	AuthEnabled        bool   `json:"auth_enabled"`
+	AuthIssuer         string `json:"auth_issuer"`
+	AuthAudience       string `json:"auth_audience"`
+	AuthJWKS           string `json:"auth_jwks"`
+	AuthJWKSRefreshSec int64  `json:"auth_jwks_refresh_sec"`
+	AuthLeewaySec      int64  `json:"auth_leeway_sec"`
+
+	PolicyFile             string `json:"policy_file"`
+	PolicyReloadIntervalMs int64  `json:"policy_reload_interval_ms"`
+
+	RateLimitEnabled bool   `json:"rate_limit_enabled"`
+	RateLimitDefault string `json:"rate_limit_default"`
+	RateLimitRoutes  string `json:"rate_limit_routes"`
+	RateLimitStore   string `json:"rate_limit_store"`
+
+	LoadShedEnabled         bool  `json:"load_shed_enabled"`
+	LoadShedInitialLimit    int64 `json:"load_shed_initial_limit"`
+	LoadShedMinLimit        int64 `json:"load_shed_min_limit"`
+	LoadShedMaxLimit        int64 `json:"load_shed_max_limit"`
+	LoadShedLatencyTargetMs int64 `json:"load_shed_latency_target_ms"`
+
+	HTTPMaxBodyBytes    int64 `json:"http_max_body_bytes"`
+	GRPCMaxRecvMsgBytes int64 `json:"grpc_max_recv_msg_bytes"`
+	GRPCMaxSendMsgBytes int64 `json:"grpc_max_send_msg_bytes"`
+
+	CompressionEnabled  bool   `json:"compression_enabled"`
+	CompressionMinBytes int64  `json:"compression_min_bytes"`
+	GRPCCompression     string `json:"grpc_compression"`
+
+	ShutdownDrainMs       int64 `json:"shutdown_drain_ms"`
+	ShutdownHookTimeoutMs int64 `json:"shutdown_hook_timeout_ms"`
+
+	HealthCheckTimeoutMs int64 `json:"health_check_timeout_ms"`
+	HealthCheckCacheMs   int64 `json:"health_check_cache_ms"`
+	GRPCHealthIntervalMs int64 `json:"grpc_health_interval_ms"`
+
+	MetricsPort string `json:"metrics_port"`
 }
 
 func toSnake(s string) string {
@@ -104,6 +279,70 @@ func (c *Config) ToSafeConfig() SafeConfig {
 		RemoteLogHttpURI:       c.RemoteLogHttpURI,
 		RemoteTraceRpcURI:      c.RemoteTraceRpcURI,
 		RemoteProfilingHttpURI: c.RemoteProfilingHttpURI,
+
+		MongoReadPreference:     c.MongoReadPreference,
+		MongoReadConcern:        c.MongoReadConcern,
+		MongoListReadPreference: c.MongoListReadPreference,
+		MongoListReadConcern:    c.MongoListReadConcern,
+		MongoMaxStalenessSec:    c.MongoMaxStalenessSec,
+		MongoCausalConsistency:  c.MongoCausalConsistency,
+
+		ReportCacheTTLSec: c.ReportCacheTTLSec,
+		ReportTimeoutMs:   c.ReportTimeoutMs,
+
+		WebhookWorkerEnabled:  c.WebhookWorkerEnabled,
+		WebhookPollIntervalMs: c.WebhookPollIntervalMs,
+		WebhookTimeoutMs:      c.WebhookTimeoutMs,
+		WebhookMaxAttempts:    c.WebhookMaxAttempts,
+		WebhookBackoffBaseMs:  c.WebhookBackoffBaseMs,
+		WebhookBackoffMaxMs:   c.WebhookBackoffMaxMs,
+
+		AttachmentStore:        c.AttachmentStore,
+		AttachmentLocalDir:     c.AttachmentLocalDir,
+		AttachmentMaxBytes:     c.AttachmentMaxBytes,
+		AttachmentAllowedTypes: c.AttachmentAllowedTypes,
+		AttachmentSniffContent: c.AttachmentSniffContent,
+
+		PriceSchedulerEnabled:    c.PriceSchedulerEnabled,
+		PriceSchedulerIntervalMs: c.PriceSchedulerIntervalMs,
+
+		AuthEnabled:        c.AuthEnabled,
+		AuthIssuer:         c.AuthIssuer,
+		AuthAudience:       c.AuthAudience,
+		AuthJWKS:           c.AuthJWKS,
+		AuthJWKSRefreshSec: c.AuthJWKSRefreshSec,
+		AuthLeewaySec:      c.AuthLeewaySec,
+
+		PolicyFile:             c.PolicyFile,
+		PolicyReloadIntervalMs: c.PolicyReloadIntervalMs,
+
+		RateLimitEnabled: c.RateLimitEnabled,
+		RateLimitDefault: c.RateLimitDefault,
+		RateLimitRoutes:  c.RateLimitRoutes,
+		RateLimitStore:   c.RateLimitStore,
+
+		LoadShedEnabled:         c.LoadShedEnabled,
+		LoadShedInitialLimit:    c.LoadShedInitialLimit,
+		LoadShedMinLimit:        c.LoadShedMinLimit,
+		LoadShedMaxLimit:        c.LoadShedMaxLimit,
+		LoadShedLatencyTargetMs: c.LoadShedLatencyTargetMs,
+
+		HTTPMaxBodyBytes:    c.HTTPMaxBodyBytes,
+		GRPCMaxRecvMsgBytes: c.GRPCMaxRecvMsgBytes,
+		GRPCMaxSendMsgBytes: c.GRPCMaxSendMsgBytes,
+
+		CompressionEnabled:  c.CompressionEnabled,
+		CompressionMinBytes: c.CompressionMinBytes,
+		GRPCCompression:     c.GRPCCompression,
+
+		ShutdownDrainMs:       c.ShutdownDrainMs,
+		ShutdownHookTimeoutMs: c.ShutdownHookTimeoutMs,
+
+		HealthCheckTimeoutMs: c.HealthCheckTimeoutMs,
+		HealthCheckCacheMs:   c.HealthCheckCacheMs,
+		GRPCHealthIntervalMs: c.GRPCHealthIntervalMs,
+
+		MetricsPort: c.MetricsPort,
 	}
 }
 
@@ -117,19 +356,57 @@ func setInt64(varName string) int64 {
 
 	val := os.Getenv(varName)
 	if val == "" {
-		log.Warn("Unset %s; fallback to 1s", varName)
+		log.Warn("Unset " + varName + "; fallback to 1s")
 		return 1000 // default value if not set
 	}
 
 	num, err := strconv.ParseInt(val, 10, 16)
 	if err != nil {
-		log.Error("Invalid %s: %v; fallback to 1s", varName, err)
+		log.Error("Invalid "+varName+"; fallback to 1s", slog.String("error", err.Error()))
 		return 1000
 	}
 
 	return int64(num)
 }
 
+// getEnv returns the value of varName, or fallback when it is unset.
+func getEnv(varName, fallback string) string {
+	if val := os.Getenv(varName); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// getInt64 parses varName as int64, or returns fallback when unset/invalid.
+func getInt64(varName string, fallback int64) int64 {
+	val := os.Getenv(varName)
+	if val == "" {
+		return fallback
+	}
+
+	num, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		log.Error("Invalid "+varName+"; using default", slog.String("error", err.Error()), slog.Int64("default", fallback))
+		return fallback
+	}
+	return num
+}
+
+// getBool parses varName as bool, or returns fallback when unset/invalid.
+func getBool(varName string, fallback bool) bool {
+	val := os.Getenv(varName)
+	if val == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Error("Invalid "+varName+"; using default", slog.String("error", err.Error()), slog.Bool("default", fallback))
+		return fallback
+	}
+	return b
+}
+
 func Instance() *Config {
 	configOnce.Do(func() {
 
@@ -150,7 +427,74 @@ func Instance() *Config {
 			RemoteLogHttpURI:       os.Getenv("REMOTE_LOG_HTTP_URI"),
 			RemoteTraceRpcURI:      os.Getenv("REMOTE_TRACE_RPC_URI"),
 			RemoteProfilingHttpURI: os.Getenv("REMOTE_PROFILING_HTTP_URI"),
+
+			MongoReadPreference:    getEnv("MONGO_READ_PREFERENCE", "primary"),
+			MongoReadConcern:       getEnv("MONGO_READ_CONCERN", "local"),
+			MongoMaxStalenessSec:   getInt64("MONGO_MAX_STALENESS_SEC", 0),
+			MongoCausalConsistency: getBool("MONGO_CAUSAL_CONSISTENCY", true),
+
+			ReportCacheTTLSec: getInt64("REPORT_CACHE_TTL_SEC", 30),
+			ReportTimeoutMs:   getInt64("REPORT_TIMEOUT_MS", 5000),
+
+			WebhookWorkerEnabled:  getBool("WEBHOOK_WORKER_ENABLED", true),
+			WebhookPollIntervalMs: getInt64("WEBHOOK_POLL_INTERVAL_MS", 1000),
+			WebhookTimeoutMs:      getInt64("WEBHOOK_TIMEOUT_MS", 5000),
+			WebhookMaxAttempts:    getInt64("WEBHOOK_MAX_ATTEMPTS", 8),
+			WebhookBackoffBaseMs:  getInt64("WEBHOOK_BACKOFF_BASE_MS", 1000),
+			WebhookBackoffMaxMs:   getInt64("WEBHOOK_BACKOFF_MAX_MS", 3600000),
+
+			AttachmentStore:        getEnv("ATTACHMENT_STORE", "gridfs"),
+			AttachmentLocalDir:     getEnv("ATTACHMENT_LOCAL_DIR", "./data/attachments"),
+			AttachmentMaxBytes:     getInt64("ATTACHMENT_MAX_BYTES", 10<<20),
+			AttachmentAllowedTypes: getEnv("ATTACHMENT_ALLOWED_TYPES", "image/jpeg,image/png,image/gif,image/webp,application/pdf"),
+			AttachmentSniffContent: getBool("ATTACHMENT_SNIFF_CONTENT", true),
+
+			PriceSchedulerEnabled:    getBool("PRICE_SCHEDULER_ENABLED", true),
+			PriceSchedulerIntervalMs: getInt64("PRICE_SCHEDULER_INTERVAL_MS", 1000),
+
+			AuthEnabled:        getBool("AUTH_ENABLED", false),
+			AuthIssuer:         os.Getenv("AUTH_ISSUER"),
+			AuthAudience:       os.Getenv("AUTH_AUDIENCE"),
+			AuthJWKS:           os.Getenv("AUTH_JWKS"),
+			AuthJWKSRefreshSec: getInt64("AUTH_JWKS_REFRESH_SEC", 300),
+			AuthLeewaySec:      getInt64("AUTH_LEEWAY_SEC", 30),
+			AuthHS256Secret:    os.Getenv("AUTH_HS256_SECRET"),
+
+			PolicyFile:             os.Getenv("POLICY_FILE"),
+			PolicyReloadIntervalMs: getInt64("POLICY_RELOAD_INTERVAL_MS", 5000),
+
+			RateLimitEnabled: getBool("RATE_LIMIT_ENABLED", false),
+			RateLimitDefault: getEnv("RATE_LIMIT_DEFAULT", "20/s:40"),
+			RateLimitRoutes:  os.Getenv("RATE_LIMIT_ROUTES"),
+			RateLimitStore:   getEnv("RATE_LIMIT_STORE", "memory"),
+
+			LoadShedEnabled:         getBool("LOAD_SHED_ENABLED", false),
+			LoadShedInitialLimit:    getInt64("LOAD_SHED_INITIAL_LIMIT", 50),
+			LoadShedMinLimit:        getInt64("LOAD_SHED_MIN_LIMIT", 5),
+			LoadShedMaxLimit:        getInt64("LOAD_SHED_MAX_LIMIT", 500),
+			LoadShedLatencyTargetMs: getInt64("LOAD_SHED_LATENCY_TARGET_MS", 250),
+
+			HTTPMaxBodyBytes:    getInt64("HTTP_MAX_BODY_BYTES", 1<<20),
+			GRPCMaxRecvMsgBytes: getInt64("GRPC_MAX_RECV_MSG_BYTES", 4<<20),
+			GRPCMaxSendMsgBytes: getInt64("GRPC_MAX_SEND_MSG_BYTES", 4<<20),
+
+			CompressionEnabled:  getBool("COMPRESSION_ENABLED", true),
+			CompressionMinBytes: getInt64("COMPRESSION_MIN_BYTES", 1024),
+			GRPCCompression:     getEnv("GRPC_COMPRESSION", "gzip"),
+
+			ShutdownDrainMs:       getInt64("SHUTDOWN_DRAIN_MS", 5000),
+			ShutdownHookTimeoutMs: getInt64("SHUTDOWN_HOOK_TIMEOUT_MS", 10000),
+
+			HealthCheckTimeoutMs: getInt64("HEALTH_CHECK_TIMEOUT_MS", 2000),
+			HealthCheckCacheMs:   getInt64("HEALTH_CHECK_CACHE_MS", 1000),
+			GRPCHealthIntervalMs: getInt64("GRPC_HEALTH_INTERVAL_MS", 1000),
+
+			MetricsPort: getEnv("METRICS_PORT", "9464"),
+
+			ClientAPIKey: os.Getenv("CLIENT_API_KEY"),
 		}
+		configInstance.MongoListReadPreference = getEnv("MONGO_LIST_READ_PREFERENCE", configInstance.MongoReadPreference)
+		configInstance.MongoListReadConcern = getEnv("MONGO_LIST_READ_CONCERN", configInstance.MongoReadConcern)
 
 		// Optional but recommended
 		if configInstance.RemoteLogHttpURI == "" {
@@ -188,6 +532,10 @@ func Instance() *Config {
 			log.Error("Missing required environment variables", slog.Any("missing", missing))
 			os.Exit(1)
 		}
+		if s := configInstance.MongoMaxStalenessSec; s > 0 && s < 90 {
+			log.Error("Invalid MONGO_MAX_STALENESS_SEC; must be 0 or at least 90", slog.Int64("value", s))
+			os.Exit(1)
+		}
 
 		attrs := StructAttrs("data", configInstance.ToSafeConfig())
 		anyAttrs := make([]any, len(attrs))
@@ -2,20 +2,32 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"simple-crud/internal/config"
 	"simple-crud/internal/logger"
+	"simple-crud/internal/metrics"
 	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 type Mongo struct {
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	// ListReadPreference / ListReadConcern are applied to scan-style
+	// queries so they can be routed away from the primary.
+	ListReadPreference *readpref.ReadPref
+	ListReadConcern    *readconcern.ReadConcern
+	causalConsistency  bool
 }
 
 var (
@@ -23,22 +35,100 @@ var (
 	once     sync.Once
 )
 
+// ReadPreference builds a read preference from its mode name (primary,
+// primaryPreferred, secondary, secondaryPreferred, nearest). maxStaleness is
+// ignored for primary, which does not accept it.
+func ReadPreference(mode string, maxStaleness time.Duration) (*readpref.ReadPref, error) {
+	m, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, err
+	}
+	if m == readpref.PrimaryMode || maxStaleness <= 0 {
+		return readpref.New(m)
+	}
+	return readpref.New(m, readpref.WithMaxStaleness(maxStaleness))
+}
+
+// ReadConcern builds a read concern from its level name (local, available,
+// majority, linearizable, snapshot).
+func ReadConcern(level string) (*readconcern.ReadConcern, error) {
+	switch level {
+	case "local":
+		return readconcern.Local(), nil
+	case "available":
+		return readconcern.Available(), nil
+	case "majority":
+		return readconcern.Majority(), nil
+	case "linearizable":
+		return readconcern.Linearizable(), nil
+	case "snapshot":
+		return readconcern.Snapshot(), nil
+	default:
+		return nil, fmt.Errorf("unknown read concern %q", level)
+	}
+}
+
+// causalReadConcern raises local and available, which causal sessions do not
+// make consistent, to majority; stronger levels are kept.
+func causalReadConcern(log *slog.Logger, varName, level string, rc *readconcern.ReadConcern) *readconcern.ReadConcern {
+	if level != "local" && level != "available" {
+		return rc
+	}
+	log.Warn(varName+" raised to majority for causal consistency", slog.String("configured", level))
+	return readconcern.Majority()
+}
+
 func Instance(globalCtx context.Context, uri, dbName string) (*Mongo, error) {
 	var err error
 
 	once.Do(func() {
 		var cfg = config.Instance()
+		var log = logger.Instance()
 
 		_uri := uri
 		if _uri == "" {
 			_uri = cfg.MongoURI
 		}
 
+		maxStaleness := time.Duration(cfg.MongoMaxStalenessSec) * time.Second
+		readPref, rpErr := ReadPreference(cfg.MongoReadPreference, maxStaleness)
+		if rpErr != nil {
+			log.Error("Invalid MongoDB read preference", slog.String("error", rpErr.Error()))
+			err = rpErr
+			return
+		}
+		readConcern, rcErr := ReadConcern(cfg.MongoReadConcern)
+		if rcErr != nil {
+			log.Error("Invalid MongoDB read concern", slog.String("error", rcErr.Error()))
+			err = rcErr
+			return
+		}
+		listReadPref, rpErr := ReadPreference(cfg.MongoListReadPreference, maxStaleness)
+		if rpErr != nil {
+			log.Error("Invalid MongoDB list read preference", slog.String("error", rpErr.Error()))
+			err = rpErr
+			return
+		}
+		listReadConcern, rcErr := ReadConcern(cfg.MongoListReadConcern)
+		if rcErr != nil {
+			log.Error("Invalid MongoDB list read concern", slog.String("error", rcErr.Error()))
+			err = rcErr
+			return
+		}
+
 		opts := options.Client().
 			ApplyURI(_uri).
-			SetMonitor(otelmongo.NewMonitor())
+			SetReadPreference(readPref).
+			SetMonitor(monitor())
+		if cfg.MongoCausalConsistency {
+			// A causal session only reads its own writes when both are
+			// majority acknowledged.
+			readConcern = causalReadConcern(log, "MONGO_READ_CONCERN", cfg.MongoReadConcern, readConcern)
+			listReadConcern = causalReadConcern(log, "MONGO_LIST_READ_CONCERN", cfg.MongoListReadConcern, listReadConcern)
+			opts.SetWriteConcern(writeconcern.Majority())
+		}
+		opts.SetReadConcern(readConcern)
 
-		var log = logger.Instance()
 		client, connErr := mongo.Connect(globalCtx, opts)
 		if connErr != nil {
 			log.Error("Failed to connect to MongoDB", slog.String("error", connErr.Error()))
@@ -55,17 +145,71 @@ func Instance(globalCtx context.Context, uri, dbName string) (*Mongo, error) {
 			return
 		}
 
-		log.Info("Connected to MongoDB successfully")
+		log.Info("Connected to MongoDB successfully",
+			slog.String("read_preference", readPref.String()),
+			slog.String("list_read_preference", listReadPref.String()),
+		)
 
 		_dbName := dbName
 		if _dbName == "" {
 			_dbName = cfg.MongoDBName
 		}
 		instance = &Mongo{
-			Client:   client,
-			Database: client.Database(_dbName),
+			Client:             client,
+			Database:           client.Database(_dbName),
+			ListReadPreference: listReadPref,
+			ListReadConcern:    listReadConcern,
+			causalConsistency:  cfg.MongoCausalConsistency,
 		}
 	})
 
 	return instance, err
 }
+
+// monitor traces each command with otelmongo and records its latency.
+func monitor() *event.CommandMonitor {
+	traced := otelmongo.NewMonitor()
+	return &event.CommandMonitor{
+		Started: traced.Started,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			traced.Succeeded(ctx, e)
+			metrics.ObserveMongo(e.CommandName, false, e.Duration)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			traced.Failed(ctx, e)
+			metrics.ObserveMongo(e.CommandName, true, e.Duration)
+		},
+	}
+}
+
+// ListCollectionOptions returns the collection options used for list and
+// search queries.
+func (m *Mongo) ListCollectionOptions() *options.CollectionOptions {
+	return options.Collection().
+		SetReadPreference(m.ListReadPreference).
+		SetReadConcern(m.ListReadConcern)
+}
+
+// WithCausalSession binds a causally consistent session to ctx so that a read
+// issued after a write in the same request observes that write, even when the
+// read is routed to a secondary; Instance makes reads and writes majority
+// for this to hold. The returned func ends the session.
+// When causal consistency is disabled ctx is returned unchanged.
+func (m *Mongo) WithCausalSession(ctx context.Context) (context.Context, func()) {
+	if !m.causalConsistency {
+		return ctx, func() {}
+	}
+
+	sess, err := m.Client.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		logger.Warn(ctx, "Failed to start MongoDB session", slog.String("exception.message", err.Error()))
+		return ctx, func() {}
+	}
+	return mongo.NewSessionContext(ctx, sess), func() { sess.EndSession(context.Background()) }
+}
+
+// Close disconnects from MongoDB, waiting for in-use connections to be
+// returned to the pool until ctx expires.
+func (m *Mongo) Close(ctx context.Context) error {
+	return m.Client.Disconnect(ctx)
+}
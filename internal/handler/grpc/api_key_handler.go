@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type APIKeyGRPCHandler struct {
+	pb.UnimplementedApiKeyServiceServer
+	Service *service.APIKeyService
+}
+
+var GrpcAPIKeyHandlerTracer = otel.Tracer("GrpcAPIKeyHandler")
+
+func NewAPIKeyGRPCHandler(svc *service.APIKeyService) *APIKeyGRPCHandler {
+	return &APIKeyGRPCHandler{
+		Service: svc,
+	}
+}
+
+func (h *APIKeyGRPCHandler) CreateKey(ctx context.Context, req *pb.ApiKey) (*pb.ApiKey, error) {
+	ctx, span := GrpcAPIKeyHandlerTracer.Start(ctx, "GrpcAPIKeyHandler.CreateKey")
+	defer span.End()
+	logger.Info(ctx, "GrpcAPIKeyHandler.CreateKey")
+
+	key := &model.APIKey{
+		Name:   req.GetName(),
+		Scopes: req.GetScopes(),
+	}
+	if req.GetExpiresAt() != nil {
+		expiresAt := req.GetExpiresAt().AsTime()
+		key.ExpiresAt = &expiresAt
+	}
+	created, err := h.Service.Create(ctx, key)
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+	return toProtoAPIKey(created), nil
+}
+
+func (h *APIKeyGRPCHandler) ListKeys(ctx context.Context, _ *emptypb.Empty) (*pb.ApiKeyList, error) {
+	ctx, span := GrpcAPIKeyHandlerTracer.Start(ctx, "GrpcAPIKeyHandler.ListKeys")
+	defer span.End()
+	logger.Info(ctx, "GrpcAPIKeyHandler.ListKeys")
+
+	keys, err := h.Service.GetAll(ctx)
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+	res := &pb.ApiKeyList{}
+	for i := range keys {
+		res.Keys = append(res.Keys, toProtoAPIKey(&keys[i]))
+	}
+	return res, nil
+}
+
+func (h *APIKeyGRPCHandler) GetKey(ctx context.Context, req *pb.ApiKeyId) (*pb.ApiKey, error) {
+	ctx, span := GrpcAPIKeyHandlerTracer.Start(ctx, "GrpcAPIKeyHandler.GetKey")
+	defer span.End()
+	logger.Info(ctx, "GrpcAPIKeyHandler.GetKey")
+
+	key, err := h.Service.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+	return toProtoAPIKey(key), nil
+}
+
+func (h *APIKeyGRPCHandler) RotateKey(ctx context.Context, req *pb.RotateApiKeyReq) (*pb.ApiKey, error) {
+	ctx, span := GrpcAPIKeyHandlerTracer.Start(ctx, "GrpcAPIKeyHandler.RotateKey")
+	defer span.End()
+	logger.Info(ctx, "GrpcAPIKeyHandler.RotateKey")
+
+	key, err := h.Service.Rotate(ctx, req.GetId(), time.Duration(req.GetGracePeriodSec())*time.Second)
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+	return toProtoAPIKey(key), nil
+}
+
+func (h *APIKeyGRPCHandler) RevokeKey(ctx context.Context, req *pb.ApiKeyId) (*pb.ApiKey, error) {
+	ctx, span := GrpcAPIKeyHandlerTracer.Start(ctx, "GrpcAPIKeyHandler.RevokeKey")
+	defer span.End()
+	logger.Info(ctx, "GrpcAPIKeyHandler.RevokeKey")
+
+	key, err := h.Service.Revoke(ctx, req.GetId())
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+	return toProtoAPIKey(key), nil
+}
+
+func toProtoAPIKey(key *model.APIKey) *pb.ApiKey {
+	res := &pb.ApiKey{
+		Id:        key.ID.Hex(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		Key:       key.Key,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+	}
+	if key.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*key.ExpiresAt)
+	}
+	if key.LastUsedAt != nil {
+		res.LastUsedAt = timestamppb.New(*key.LastUsedAt)
+	}
+	if key.RevokedAt != nil {
+		res.RevokedAt = timestamppb.New(*key.RevokedAt)
+	}
+	if key.RotatedAt != nil {
+		res.RotatedAt = timestamppb.New(*key.RotatedAt)
+	}
+	return res
+}
+
+func apiKeyError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		return invalidArgument(err)
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrAPIKeyRevoked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
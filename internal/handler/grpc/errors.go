@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"errors"
+
+	"simple-crud/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invalidArgument reports err as InvalidArgument. Field errors from a
+// service.ValidationError travel as a google.rpc.BadRequest detail, which
+// the HTTP gateway turns into the problem's "errors" member.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	var verr *service.ValidationError
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+	br := &errdetails.BadRequest{}
+	for _, f := range verr.Fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+	if withDetails, derr := st.WithDetails(br); derr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
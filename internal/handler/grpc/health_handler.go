@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"simple-crud/internal/health"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// HealthGRPCHandler implements grpc.health.v1.Health. Run evaluates the
+// readiness checks every interval and records a status for the server ("")
+// and each served service; Check answers from the record and Watch streams
+// its changes. Every service needs MongoDB, so they share the readiness
+// result, and all turn NOT_SERVING for the shutdown drain.
+type HealthGRPCHandler struct {
+	healthpb.UnimplementedHealthServer
+	Service  *service.HealthService
+	interval time.Duration
+
+	mu       sync.Mutex
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	// changed is closed, and replaced, whenever statuses change.
+	changed chan struct{}
+	stopped bool
+}
+
+var GrpcHealthHandlerTracer = otel.Tracer("GrpcHealthHandler")
+
+// NewHealthGRPCHandler serves the health of services, which start out
+// NOT_SERVING until the first evaluation.
+func NewHealthGRPCHandler(svc *service.HealthService, services []string, interval time.Duration) *HealthGRPCHandler {
+	statuses := map[string]healthpb.HealthCheckResponse_ServingStatus{"": healthpb.HealthCheckResponse_NOT_SERVING}
+	for _, name := range services {
+		statuses[name] = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return &HealthGRPCHandler{
+		Service:  svc,
+		interval: interval,
+		statuses: statuses,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Run keeps the statuses current until ctx ends, then sets them all to
+// NOT_SERVING and ends the Watch streams, so a graceful stop is not held
+// up by them.
+func (h *HealthGRPCHandler) Run(ctx context.Context) {
+	h.Service.WatchReady(ctx, h.interval, h.set)
+	h.stop()
+}
+
+func (h *HealthGRPCHandler) set(rep health.Report) {
+	serving := healthpb.HealthCheckResponse_SERVING
+	if !rep.Up() {
+		serving = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopped {
+		return
+	}
+	for name := range h.statuses {
+		h.statuses[name] = serving
+	}
+	h.notifyLocked()
+}
+
+func (h *HealthGRPCHandler) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	for name := range h.statuses {
+		h.statuses[name] = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.notifyLocked()
+}
+
+func (h *HealthGRPCHandler) notifyLocked() {
+	close(h.changed)
+	h.changed = make(chan struct{})
+}
+
+// lookup returns the status of service, whether it is known, a channel
+// closed on the next change, and whether the handler has stopped.
+func (h *HealthGRPCHandler) lookup(service string) (healthpb.HealthCheckResponse_ServingStatus, bool, <-chan struct{}, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.statuses[service]
+	return s, ok, h.changed, h.stopped
+}
+
+func (h *HealthGRPCHandler) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	ctx, span := GrpcHealthHandlerTracer.Start(ctx, "GrpcHealthHandler.Check")
+	defer span.End()
+	logger.Info(ctx, "GrpcHealthHandler.Check")
+
+	s, ok, _, _ := h.lookup(req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: s}, nil
+}
+
+// Watch sends the service's status, then every change to it, until the
+// client goes away or the server stops. An unknown service is reported as
+// SERVICE_UNKNOWN, as the protocol asks, rather than failed.
+func (h *HealthGRPCHandler) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, span := GrpcHealthHandlerTracer.Start(stream.Context(), "GrpcHealthHandler.Watch")
+	defer span.End()
+	logger.Info(ctx, "GrpcHealthHandler.Watch")
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		s, ok, changed, stopped := h.lookup(req.GetService())
+		if !ok {
+			s = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if s != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: s}); err != nil {
+				return err
+			}
+			last = s
+		}
+		if stopped {
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
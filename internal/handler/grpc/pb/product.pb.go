@@ -7,9 +7,11 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -28,6 +30,9 @@ type Product struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
 	Stock         int32                  `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Attachments   []*Attachment          `protobuf:"bytes,7,rep,name=attachments,proto3" json:"attachments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,16 +95,116 @@ func (x *Product) GetStock() int32 {
 	return 0
 }
 
-type ProductId struct {
+func (x *Product) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Product) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Product) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// Attachment is metadata only; content is served over HTTP.
+type Attachment struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	UploadedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=uploaded_at,json=uploadedAt,proto3" json:"uploaded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attachment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Attachment) GetUploadedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UploadedAt
+	}
+	return nil
+}
+
+type ProductId struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// GetByID only: return the price that was in effect at this time.
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductId) Reset() {
 	*x = ProductId{}
-	mi := &file_product_proto_msgTypes[1]
+	mi := &file_product_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -111,7 +216,7 @@ func (x *ProductId) String() string {
 func (*ProductId) ProtoMessage() {}
 
 func (x *ProductId) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[1]
+	mi := &file_product_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -124,7 +229,7 @@ func (x *ProductId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductId.ProtoReflect.Descriptor instead.
 func (*ProductId) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{1}
+	return file_product_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ProductId) GetId() string {
@@ -134,6 +239,13 @@ func (x *ProductId) GetId() string {
 	return ""
 }
 
+func (x *ProductId) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
 type ProductRes1 struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Resolver      string                 `protobuf:"bytes,1,opt,name=resolver,proto3" json:"resolver,omitempty"`
@@ -144,7 +256,7 @@ type ProductRes1 struct {
 
 func (x *ProductRes1) Reset() {
 	*x = ProductRes1{}
-	mi := &file_product_proto_msgTypes[2]
+	mi := &file_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -156,7 +268,7 @@ func (x *ProductRes1) String() string {
 func (*ProductRes1) ProtoMessage() {}
 
 func (x *ProductRes1) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[2]
+	mi := &file_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -169,7 +281,7 @@ func (x *ProductRes1) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductRes1.ProtoReflect.Descriptor instead.
 func (*ProductRes1) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{2}
+	return file_product_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ProductRes1) GetResolver() string {
@@ -196,7 +308,7 @@ type ProductResN struct {
 
 func (x *ProductResN) Reset() {
 	*x = ProductResN{}
-	mi := &file_product_proto_msgTypes[3]
+	mi := &file_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -208,7 +320,7 @@ func (x *ProductResN) String() string {
 func (*ProductResN) ProtoMessage() {}
 
 func (x *ProductResN) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[3]
+	mi := &file_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -221,7 +333,7 @@ func (x *ProductResN) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResN.ProtoReflect.Descriptor instead.
 func (*ProductResN) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{3}
+	return file_product_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ProductResN) GetResolver() string {
@@ -238,30 +350,1926 @@ func (x *ProductResN) GetProducts() []*Product {
 	return nil
 }
 
-var File_product_proto protoreflect.FileDescriptor
+type StreamProductsReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of products per chunk; the server picks a default when unset.
+	ChunkSize     int32 `protobuf:"varint,1,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_product_proto_rawDesc = "" +
-	"\n" +
-	"\rproduct.proto\x12\aproduct\x1a\x1bgoogle/protobuf/empty.proto\"Y\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x14\n" +
-	"\x05stock\x18\x04 \x01(\x05R\x05stock\"\x1b\n" +
-	"\tProductId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"U\n" +
-	"\vProductRes1\x12\x1a\n" +
-	"\bresolver\x18\x01 \x01(\tR\bresolver\x12*\n" +
-	"\aproduct\x18\x02 \x01(\v2\x10.product.ProductR\aproduct\"W\n" +
-	"\vProductResN\x12\x1a\n" +
-	"\bresolver\x18\x01 \x01(\tR\bresolver\x12,\n" +
-	"\bproducts\x18\x02 \x03(\v2\x10.product.ProductR\bproducts2\x97\x02\n" +
-	"\x0eProductService\x126\n" +
-	"\x06GetAll\x12\x16.google.protobuf.Empty\x1a\x14.product.ProductResN\x123\n" +
-	"\aGetByID\x12\x12.product.ProductId\x1a\x14.product.ProductRes1\x120\n" +
-	"\x06Create\x12\x10.product.Product\x1a\x14.product.ProductRes1\x120\n" +
-	"\x06Update\x12\x10.product.Product\x1a\x14.product.ProductRes1\x124\n" +
-	"\x06Delete\x12\x12.product.ProductId\x1a\x16.google.protobuf.EmptyB)Z'simple-crud/internal/handler/grpc/pb;pbb\x06proto3"
+func (x *StreamProductsReq) Reset() {
+	*x = StreamProductsReq{}
+	mi := &file_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamProductsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamProductsReq) ProtoMessage() {}
+
+func (x *StreamProductsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamProductsReq.ProtoReflect.Descriptor instead.
+func (*StreamProductsReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamProductsReq) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+type ProductChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resolver      string                 `protobuf:"bytes,1,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	Seq           int64                  `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductChunk) Reset() {
+	*x = ProductChunk{}
+	mi := &file_product_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductChunk) ProtoMessage() {}
+
+func (x *ProductChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductChunk.ProtoReflect.Descriptor instead.
+func (*ProductChunk) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProductChunk) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *ProductChunk) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ProductChunk) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type InventoryValueRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resolver      string                 `protobuf:"bytes,1,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	TotalValue    float64                `protobuf:"fixed64,2,opt,name=total_value,json=totalValue,proto3" json:"total_value,omitempty"`
+	TotalStock    int64                  `protobuf:"varint,3,opt,name=total_stock,json=totalStock,proto3" json:"total_stock,omitempty"`
+	ProductCount  int64                  `protobuf:"varint,4,opt,name=product_count,json=productCount,proto3" json:"product_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryValueRes) Reset() {
+	*x = InventoryValueRes{}
+	mi := &file_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryValueRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryValueRes) ProtoMessage() {}
+
+func (x *InventoryValueRes) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryValueRes.ProtoReflect.Descriptor instead.
+func (*InventoryValueRes) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InventoryValueRes) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *InventoryValueRes) GetTotalValue() float64 {
+	if x != nil {
+		return x.TotalValue
+	}
+	return 0
+}
+
+func (x *InventoryValueRes) GetTotalStock() int64 {
+	if x != nil {
+		return x.TotalStock
+	}
+	return 0
+}
+
+func (x *InventoryValueRes) GetProductCount() int64 {
+	if x != nil {
+		return x.ProductCount
+	}
+	return 0
+}
+
+type LowStockReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 10 when unset.
+	Threshold     *int32 `protobuf:"varint,1,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	Limit         int64  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LowStockReq) Reset() {
+	*x = LowStockReq{}
+	mi := &file_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LowStockReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LowStockReq) ProtoMessage() {}
+
+func (x *LowStockReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LowStockReq.ProtoReflect.Descriptor instead.
+func (*LowStockReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LowStockReq) GetThreshold() int32 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *LowStockReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PriceHistogramReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sorted bucket boundaries; when empty, `buckets` auto-sized ranges are used.
+	Boundaries    []float64 `protobuf:"fixed64,1,rep,packed,name=boundaries,proto3" json:"boundaries,omitempty"`
+	Buckets       int32     `protobuf:"varint,2,opt,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistogramReq) Reset() {
+	*x = PriceHistogramReq{}
+	mi := &file_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistogramReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistogramReq) ProtoMessage() {}
+
+func (x *PriceHistogramReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistogramReq.ProtoReflect.Descriptor instead.
+func (*PriceHistogramReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PriceHistogramReq) GetBoundaries() []float64 {
+	if x != nil {
+		return x.Boundaries
+	}
+	return nil
+}
+
+func (x *PriceHistogramReq) GetBuckets() int32 {
+	if x != nil {
+		return x.Buckets
+	}
+	return 0
+}
+
+type PriceBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset bounds mark open-ended buckets.
+	Min           *float64 `protobuf:"fixed64,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max           *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Count         int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceBucket) Reset() {
+	*x = PriceBucket{}
+	mi := &file_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceBucket) ProtoMessage() {}
+
+func (x *PriceBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceBucket.ProtoReflect.Descriptor instead.
+func (*PriceBucket) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PriceBucket) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *PriceBucket) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *PriceBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PriceHistogramRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resolver      string                 `protobuf:"bytes,1,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	Buckets       []*PriceBucket         `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistogramRes) Reset() {
+	*x = PriceHistogramRes{}
+	mi := &file_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistogramRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistogramRes) ProtoMessage() {}
+
+func (x *PriceHistogramRes) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistogramRes.ProtoReflect.Descriptor instead.
+func (*PriceHistogramRes) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PriceHistogramRes) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *PriceHistogramRes) GetBuckets() []*PriceBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type CountByReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "category" (default) or "tag".
+	By            string `protobuf:"bytes,1,opt,name=by,proto3" json:"by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountByReq) Reset() {
+	*x = CountByReq{}
+	mi := &file_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountByReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountByReq) ProtoMessage() {}
+
+func (x *CountByReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountByReq.ProtoReflect.Descriptor instead.
+func (*CountByReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CountByReq) GetBy() string {
+	if x != nil {
+		return x.By
+	}
+	return ""
+}
+
+type GroupCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Stock         int64                  `protobuf:"varint,3,opt,name=stock,proto3" json:"stock,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupCount) Reset() {
+	*x = GroupCount{}
+	mi := &file_product_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupCount) ProtoMessage() {}
+
+func (x *GroupCount) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupCount.ProtoReflect.Descriptor instead.
+func (*GroupCount) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GroupCount) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GroupCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GroupCount) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *GroupCount) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type CountByRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resolver      string                 `protobuf:"bytes,1,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	By            string                 `protobuf:"bytes,2,opt,name=by,proto3" json:"by,omitempty"`
+	Groups        []*GroupCount          `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountByRes) Reset() {
+	*x = CountByRes{}
+	mi := &file_product_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountByRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountByRes) ProtoMessage() {}
+
+func (x *CountByRes) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountByRes.ProtoReflect.Descriptor instead.
+func (*CountByRes) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CountByRes) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *CountByRes) GetBy() string {
+	if x != nil {
+		return x.By
+	}
+	return ""
+}
+
+func (x *CountByRes) GetGroups() []*GroupCount {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type WebhookSubscription struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url    string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	// Only returned on create; an empty secret on update keeps the current one.
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	Disabled      bool                   `protobuf:"varint,5,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookSubscription) Reset() {
+	*x = WebhookSubscription{}
+	mi := &file_product_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookSubscription) ProtoMessage() {}
+
+func (x *WebhookSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookSubscription.ProtoReflect.Descriptor instead.
+func (*WebhookSubscription) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WebhookSubscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *WebhookSubscription) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *WebhookSubscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookSubscription) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type WebhookId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookId) Reset() {
+	*x = WebhookId{}
+	mi := &file_product_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookId) ProtoMessage() {}
+
+func (x *WebhookId) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookId.ProtoReflect.Descriptor instead.
+func (*WebhookId) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WebhookId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WebhookSubscriptionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*WebhookSubscription `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookSubscriptionList) Reset() {
+	*x = WebhookSubscriptionList{}
+	mi := &file_product_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookSubscriptionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookSubscriptionList) ProtoMessage() {}
+
+func (x *WebhookSubscriptionList) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookSubscriptionList.ProtoReflect.Descriptor instead.
+func (*WebhookSubscriptionList) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WebhookSubscriptionList) GetSubscriptions() []*WebhookSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type ListDeliveriesReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// pending, delivered or dead; empty matches all.
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int64  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveriesReq) Reset() {
+	*x = ListDeliveriesReq{}
+	mi := &file_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveriesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveriesReq) ProtoMessage() {}
+
+func (x *ListDeliveriesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveriesReq.ProtoReflect.Descriptor instead.
+func (*ListDeliveriesReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListDeliveriesReq) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ListDeliveriesReq) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListDeliveriesReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DeliveryAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=at,proto3" json:"at,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryAttempt) Reset() {
+	*x = DeliveryAttempt{}
+	mi := &file_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryAttempt) ProtoMessage() {}
+
+func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryAttempt.ProtoReflect.Descriptor instead.
+func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeliveryAttempt) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Event          string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	Payload        string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts       int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastError      string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	History        []*DeliveryAttempt     `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeliveredAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetHistory() []*DeliveryAttempt {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+type WebhookDeliveryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDeliveryList) Reset() {
+	*x = WebhookDeliveryList{}
+	mi := &file_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeliveryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveryList) ProtoMessage() {}
+
+func (x *WebhookDeliveryList) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveryList.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveryList) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WebhookDeliveryList) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type PriceSchedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	PreviousPrice *float64               `protobuf:"fixed64,7,opt,name=previous_price,json=previousPrice,proto3,oneof" json:"previous_price,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AppliedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceSchedule) Reset() {
+	*x = PriceSchedule{}
+	mi := &file_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSchedule) ProtoMessage() {}
+
+func (x *PriceSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSchedule.ProtoReflect.Descriptor instead.
+func (*PriceSchedule) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PriceSchedule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PriceSchedule) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceSchedule) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceSchedule) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *PriceSchedule) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *PriceSchedule) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PriceSchedule) GetPreviousPrice() float64 {
+	if x != nil && x.PreviousPrice != nil {
+		return *x.PreviousPrice
+	}
+	return 0
+}
+
+func (x *PriceSchedule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PriceSchedule) GetAppliedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AppliedAt
+	}
+	return nil
+}
+
+func (x *PriceSchedule) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type PriceScheduleId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceScheduleId) Reset() {
+	*x = PriceScheduleId{}
+	mi := &file_product_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceScheduleId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceScheduleId) ProtoMessage() {}
+
+func (x *PriceScheduleId) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceScheduleId.ProtoReflect.Descriptor instead.
+func (*PriceScheduleId) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PriceScheduleId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PriceScheduleList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*PriceSchedule       `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceScheduleList) Reset() {
+	*x = PriceScheduleList{}
+	mi := &file_product_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceScheduleList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceScheduleList) ProtoMessage() {}
+
+func (x *PriceScheduleList) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceScheduleList.ProtoReflect.Descriptor instead.
+func (*PriceScheduleList) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PriceScheduleList) GetSchedules() []*PriceSchedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+type PriceHistoryReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistoryReq) Reset() {
+	*x = PriceHistoryReq{}
+	mi := &file_product_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistoryReq) ProtoMessage() {}
+
+func (x *PriceHistoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistoryReq.ProtoReflect.Descriptor instead.
+func (*PriceHistoryReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PriceHistoryReq) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceHistoryReq) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *PriceHistoryReq) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type PriceChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
+	EffectiveTo   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=effective_to,json=effectiveTo,proto3" json:"effective_to,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,7,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceChange) Reset() {
+	*x = PriceChange{}
+	mi := &file_product_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceChange) ProtoMessage() {}
+
+func (x *PriceChange) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceChange.ProtoReflect.Descriptor instead.
+func (*PriceChange) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PriceChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PriceChange) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceChange) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceChange) GetEffectiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return nil
+}
+
+func (x *PriceChange) GetEffectiveTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveTo
+	}
+	return nil
+}
+
+func (x *PriceChange) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PriceChange) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type PriceHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*PriceChange         `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistory) Reset() {
+	*x = PriceHistory{}
+	mi := &file_product_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistory) ProtoMessage() {}
+
+func (x *PriceHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistory.ProtoReflect.Descriptor instead.
+func (*PriceHistory) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PriceHistory) GetChanges() []*PriceChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// ApiKey is a service-to-service credential, sent as X-API-Key (gRPC:
+// x-api-key metadata). Only a hash is stored.
+type ApiKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Stable public part of the key, e.g. for telling keys apart in lists.
+	Prefix string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Scopes []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// The key itself; only returned by CreateKey and RotateKey.
+	Key           string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	RevokedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	RotatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKey) Reset() {
+	*x = ApiKey{}
+	mi := &file_product_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKey) ProtoMessage() {}
+
+func (x *ApiKey) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKey.ProtoReflect.Descriptor instead.
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ApiKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApiKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ApiKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetRotatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RotatedAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ApiKeyId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyId) Reset() {
+	*x = ApiKeyId{}
+	mi := &file_product_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyId) ProtoMessage() {}
+
+func (x *ApiKeyId) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyId.ProtoReflect.Descriptor instead.
+func (*ApiKeyId) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ApiKeyId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RotateApiKeyReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// How long the replaced key keeps working; 0 invalidates it at once.
+	GracePeriodSec int64 `protobuf:"varint,2,opt,name=grace_period_sec,json=gracePeriodSec,proto3" json:"grace_period_sec,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateApiKeyReq) Reset() {
+	*x = RotateApiKeyReq{}
+	mi := &file_product_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateApiKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateApiKeyReq) ProtoMessage() {}
+
+func (x *RotateApiKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateApiKeyReq.ProtoReflect.Descriptor instead.
+func (*RotateApiKeyReq) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RotateApiKeyReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateApiKeyReq) GetGracePeriodSec() int64 {
+	if x != nil {
+		return x.GracePeriodSec
+	}
+	return 0
+}
+
+type ApiKeyList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*ApiKey              `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKeyList) Reset() {
+	*x = ApiKeyList{}
+	mi := &file_product_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKeyList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKeyList) ProtoMessage() {}
+
+func (x *ApiKeyList) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKeyList.ProtoReflect.Descriptor instead.
+func (*ApiKeyList) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ApiKeyList) GetKeys() []*ApiKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+var File_product_proto protoreflect.FileDescriptor
+
+const file_product_proto_rawDesc = "" +
+	"\n" +
+	"\rproduct.proto\x12\aproduct\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc0\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x04 \x01(\x05R\x05stock\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x125\n" +
+	"\vattachments\x18\a \x03(\v2\x13.product.AttachmentR\vattachments\"\xac\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12;\n" +
+	"\vuploaded_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"uploadedAt\"L\n" +
+	"\tProductId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\x05as_of\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"U\n" +
+	"\vProductRes1\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12*\n" +
+	"\aproduct\x18\x02 \x01(\v2\x10.product.ProductR\aproduct\"W\n" +
+	"\vProductResN\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12,\n" +
+	"\bproducts\x18\x02 \x03(\v2\x10.product.ProductR\bproducts\"2\n" +
+	"\x11StreamProductsReq\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x01 \x01(\x05R\tchunkSize\"j\n" +
+	"\fProductChunk\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12,\n" +
+	"\bproducts\x18\x02 \x03(\v2\x10.product.ProductR\bproducts\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x03R\x03seq\"\x96\x01\n" +
+	"\x11InventoryValueRes\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12\x1f\n" +
+	"\vtotal_value\x18\x02 \x01(\x01R\n" +
+	"totalValue\x12\x1f\n" +
+	"\vtotal_stock\x18\x03 \x01(\x03R\n" +
+	"totalStock\x12#\n" +
+	"\rproduct_count\x18\x04 \x01(\x03R\fproductCount\"T\n" +
+	"\vLowStockReq\x12!\n" +
+	"\tthreshold\x18\x01 \x01(\x05H\x00R\tthreshold\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limitB\f\n" +
+	"\n" +
+	"_threshold\"M\n" +
+	"\x11PriceHistogramReq\x12\x1e\n" +
+	"\n" +
+	"boundaries\x18\x01 \x03(\x01R\n" +
+	"boundaries\x12\x18\n" +
+	"\abuckets\x18\x02 \x01(\x05R\abuckets\"a\n" +
+	"\vPriceBucket\x12\x15\n" +
+	"\x03min\x18\x01 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x01H\x01R\x03max\x88\x01\x01\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05countB\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_max\"_\n" +
+	"\x11PriceHistogramRes\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12.\n" +
+	"\abuckets\x18\x02 \x03(\v2\x14.product.PriceBucketR\abuckets\"\x1c\n" +
+	"\n" +
+	"CountByReq\x12\x0e\n" +
+	"\x02by\x18\x01 \x01(\tR\x02by\"`\n" +
+	"\n" +
+	"GroupCount\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x14\n" +
+	"\x05stock\x18\x03 \x01(\x03R\x05stock\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\"e\n" +
+	"\n" +
+	"CountByRes\x12\x1a\n" +
+	"\bresolver\x18\x01 \x01(\tR\bresolver\x12\x0e\n" +
+	"\x02by\x18\x02 \x01(\tR\x02by\x12+\n" +
+	"\x06groups\x18\x03 \x03(\v2\x13.product.GroupCountR\x06groups\"\xf9\x01\n" +
+	"\x13WebhookSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x12\x1a\n" +
+	"\bdisabled\x18\x05 \x01(\bR\bdisabled\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x1b\n" +
+	"\tWebhookId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"]\n" +
+	"\x17WebhookSubscriptionList\x12B\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1c.product.WebhookSubscriptionR\rsubscriptions\"j\n" +
+	"\x11ListDeliveriesReq\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\"\x95\x01\n" +
+	"\x0fDeliveryAttempt\x12*\n" +
+	"\x02at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x1f\n" +
+	"\vstatus_code\x18\x02 \x01(\x05R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\"\xbf\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x122\n" +
+	"\ahistory\x18\t \x03(\v2\x18.product.DeliveryAttemptR\ahistory\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fdelivered_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\"O\n" +
+	"\x13WebhookDeliveryList\x128\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x18.product.WebhookDeliveryR\n" +
+	"deliveries\"\xce\x03\n" +
+	"\rPriceSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x127\n" +
+	"\tstarts_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12*\n" +
+	"\x0eprevious_price\x18\a \x01(\x01H\x00R\rpreviousPrice\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"applied_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tappliedAt\x12=\n" +
+	"\fcompleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAtB\x11\n" +
+	"\x0f_previous_price\"!\n" +
+	"\x0fPriceScheduleId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"I\n" +
+	"\x11PriceScheduleList\x124\n" +
+	"\tschedules\x18\x01 \x03(\v2\x16.product.PriceScheduleR\tschedules\"\x8c\x01\n" +
+	"\x0fPriceHistoryReq\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\x8d\x02\n" +
+	"\vPriceChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12A\n" +
+	"\x0eeffective_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\x12=\n" +
+	"\feffective_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveTo\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x12\x1f\n" +
+	"\vschedule_id\x18\a \x01(\tR\n" +
+	"scheduleId\">\n" +
+	"\fPriceHistory\x12.\n" +
+	"\achanges\x18\x01 \x03(\v2\x14.product.PriceChangeR\achanges\"\x98\x03\n" +
+	"\x06ApiKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12<\n" +
+	"\flast_used_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x129\n" +
+	"\n" +
+	"rotated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\trotatedAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x1a\n" +
+	"\bApiKeyId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
+	"\x0fRotateApiKeyReq\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x10grace_period_sec\x18\x02 \x01(\x03R\x0egracePeriodSec\"1\n" +
+	"\n" +
+	"ApiKeyList\x12#\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0f.product.ApiKeyR\x04keys2\xf7\x03\n" +
+	"\x0eProductService\x12S\n" +
+	"\x06GetAll\x12\x16.google.protobuf.Empty\x1a\x14.product.ProductResN\"\x1b\x82\xd3\xe4\x93\x02\x15b\bproducts\x12\t/products\x12T\n" +
+	"\aGetByID\x12\x12.product.ProductId\x1a\x14.product.ProductRes1\"\x1f\x82\xd3\xe4\x93\x02\x19b\aproduct\x12\x0e/products/{id}\x12O\n" +
+	"\x06Create\x12\x10.product.Product\x1a\x14.product.ProductRes1\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*b\aproduct\"\t/products\x12T\n" +
+	"\x06Update\x12\x10.product.Product\x1a\x14.product.ProductRes1\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*b\aproduct\x1a\x0e/products/{id}\x12L\n" +
+	"\x06Delete\x12\x12.product.ProductId\x1a\x16.google.protobuf.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/products/{id}\x12E\n" +
+	"\x0eStreamProducts\x12\x1a.product.StreamProductsReq\x1a\x15.product.ProductChunk0\x012\xa0\x03\n" +
+	"\rReportService\x12f\n" +
+	"\x0eInventoryValue\x12\x16.google.protobuf.Empty\x1a\x1a.product.InventoryValueRes\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/reports/inventory-value\x12\\\n" +
+	"\bLowStock\x12\x14.product.LowStockReq\x1a\x14.product.ProductResN\"$\x82\xd3\xe4\x93\x02\x1eb\bproducts\x12\x12/reports/low-stock\x12s\n" +
+	"\x0ePriceHistogram\x12\x1a.product.PriceHistogramReq\x1a\x1a.product.PriceHistogramRes\")\x82\xd3\xe4\x93\x02#b\abuckets\x12\x18/reports/price-histogram\x12T\n" +
+	"\aCountBy\x12\x13.product.CountByReq\x1a\x13.product.CountByRes\"\x1f\x82\xd3\xe4\x93\x02\x19b\x06groups\x12\x0f/reports/counts2\xfe\x05\n" +
+	"\x0eWebhookService\x12f\n" +
+	"\x12CreateSubscription\x12\x1c.product.WebhookSubscription\x1a\x1c.product.WebhookSubscription\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/webhooks\x12[\n" +
+	"\x0fGetSubscription\x12\x12.product.WebhookId\x1a\x1c.product.WebhookSubscription\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/webhooks/{id}\x12o\n" +
+	"\x11ListSubscriptions\x12\x16.google.protobuf.Empty\x1a .product.WebhookSubscriptionList\" \x82\xd3\xe4\x93\x02\x1ab\rsubscriptions\x12\t/webhooks\x12k\n" +
+	"\x12UpdateSubscription\x12\x1c.product.WebhookSubscription\x1a\x1c.product.WebhookSubscription\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\x1a\x0e/webhooks/{id}\x12X\n" +
+	"\x12DeleteSubscription\x12\x12.product.WebhookId\x1a\x16.google.protobuf.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/webhooks/{id}\x12\x86\x01\n" +
+	"\x0eListDeliveries\x12\x1a.product.ListDeliveriesReq\x1a\x1c.product.WebhookDeliveryList\":\x82\xd3\xe4\x93\x024b\n" +
+	"deliveries\x12&/webhooks/{subscription_id}/deliveries\x12f\n" +
+	"\rRetryDelivery\x12\x12.product.WebhookId\x1a\x18.product.WebhookDelivery\"'\x82\xd3\xe4\x93\x02!\"\x1f/webhooks/deliveries/{id}/retry2\xd4\x03\n" +
+	"\fPriceService\x12t\n" +
+	"\x0eCreateSchedule\x12\x16.product.PriceSchedule\x1a\x16.product.PriceSchedule\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/products/{product_id}/prices/schedules\x12s\n" +
+	"\rListSchedules\x12\x12.product.ProductId\x1a\x1a.product.PriceScheduleList\"2\x82\xd3\xe4\x93\x02,b\tschedules\x12\x1f/products/{id}/prices/schedules\x12b\n" +
+	"\x0eCancelSchedule\x12\x18.product.PriceScheduleId\x1a\x16.product.PriceSchedule\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/prices/schedules/{id}\x12u\n" +
+	"\n" +
+	"GetHistory\x12\x18.product.PriceHistoryReq\x1a\x15.product.PriceHistory\"6\x82\xd3\xe4\x93\x020b\achanges\x12%/products/{product_id}/prices/history2\xad\x03\n" +
+	"\rApiKeyService\x12I\n" +
+	"\tCreateKey\x12\x0f.product.ApiKey\x1a\x0f.product.ApiKey\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/admin/api-keys\x12V\n" +
+	"\bListKeys\x12\x16.google.protobuf.Empty\x1a\x13.product.ApiKeyList\"\x1d\x82\xd3\xe4\x93\x02\x17b\x04keys\x12\x0f/admin/api-keys\x12J\n" +
+	"\x06GetKey\x12\x11.product.ApiKeyId\x1a\x0f.product.ApiKey\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/admin/api-keys/{id}\x12^\n" +
+	"\tRotateKey\x12\x18.product.RotateApiKeyReq\x1a\x0f.product.ApiKey\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/admin/api-keys/{id}/rotate\x12M\n" +
+	"\tRevokeKey\x12\x11.product.ApiKeyId\x1a\x0f.product.ApiKey\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/admin/api-keys/{id}B)Z'simple-crud/internal/handler/grpc/pb;pbb\x06proto3"
 
 var (
 	file_product_proto_rawDescOnce sync.Once
@@ -275,32 +2283,135 @@ func file_product_proto_rawDescGZIP() []byte {
 	return file_product_proto_rawDescData
 }
 
-var file_product_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_product_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_product_proto_goTypes = []any{
-	(*Product)(nil),       // 0: product.Product
-	(*ProductId)(nil),     // 1: product.ProductId
-	(*ProductRes1)(nil),   // 2: product.ProductRes1
-	(*ProductResN)(nil),   // 3: product.ProductResN
-	(*emptypb.Empty)(nil), // 4: google.protobuf.Empty
+	(*Product)(nil),                 // 0: product.Product
+	(*Attachment)(nil),              // 1: product.Attachment
+	(*ProductId)(nil),               // 2: product.ProductId
+	(*ProductRes1)(nil),             // 3: product.ProductRes1
+	(*ProductResN)(nil),             // 4: product.ProductResN
+	(*StreamProductsReq)(nil),       // 5: product.StreamProductsReq
+	(*ProductChunk)(nil),            // 6: product.ProductChunk
+	(*InventoryValueRes)(nil),       // 7: product.InventoryValueRes
+	(*LowStockReq)(nil),             // 8: product.LowStockReq
+	(*PriceHistogramReq)(nil),       // 9: product.PriceHistogramReq
+	(*PriceBucket)(nil),             // 10: product.PriceBucket
+	(*PriceHistogramRes)(nil),       // 11: product.PriceHistogramRes
+	(*CountByReq)(nil),              // 12: product.CountByReq
+	(*GroupCount)(nil),              // 13: product.GroupCount
+	(*CountByRes)(nil),              // 14: product.CountByRes
+	(*WebhookSubscription)(nil),     // 15: product.WebhookSubscription
+	(*WebhookId)(nil),               // 16: product.WebhookId
+	(*WebhookSubscriptionList)(nil), // 17: product.WebhookSubscriptionList
+	(*ListDeliveriesReq)(nil),       // 18: product.ListDeliveriesReq
+	(*DeliveryAttempt)(nil),         // 19: product.DeliveryAttempt
+	(*WebhookDelivery)(nil),         // 20: product.WebhookDelivery
+	(*WebhookDeliveryList)(nil),     // 21: product.WebhookDeliveryList
+	(*PriceSchedule)(nil),           // 22: product.PriceSchedule
+	(*PriceScheduleId)(nil),         // 23: product.PriceScheduleId
+	(*PriceScheduleList)(nil),       // 24: product.PriceScheduleList
+	(*PriceHistoryReq)(nil),         // 25: product.PriceHistoryReq
+	(*PriceChange)(nil),             // 26: product.PriceChange
+	(*PriceHistory)(nil),            // 27: product.PriceHistory
+	(*ApiKey)(nil),                  // 28: product.ApiKey
+	(*ApiKeyId)(nil),                // 29: product.ApiKeyId
+	(*RotateApiKeyReq)(nil),         // 30: product.RotateApiKeyReq
+	(*ApiKeyList)(nil),              // 31: product.ApiKeyList
+	(*timestamppb.Timestamp)(nil),   // 32: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),           // 33: google.protobuf.Empty
 }
 var file_product_proto_depIdxs = []int32{
-	0, // 0: product.ProductRes1.product:type_name -> product.Product
-	0, // 1: product.ProductResN.products:type_name -> product.Product
-	4, // 2: product.ProductService.GetAll:input_type -> google.protobuf.Empty
-	1, // 3: product.ProductService.GetByID:input_type -> product.ProductId
-	0, // 4: product.ProductService.Create:input_type -> product.Product
-	0, // 5: product.ProductService.Update:input_type -> product.Product
-	1, // 6: product.ProductService.Delete:input_type -> product.ProductId
-	3, // 7: product.ProductService.GetAll:output_type -> product.ProductResN
-	2, // 8: product.ProductService.GetByID:output_type -> product.ProductRes1
-	2, // 9: product.ProductService.Create:output_type -> product.ProductRes1
-	2, // 10: product.ProductService.Update:output_type -> product.ProductRes1
-	4, // 11: product.ProductService.Delete:output_type -> google.protobuf.Empty
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: product.Product.attachments:type_name -> product.Attachment
+	32, // 1: product.Attachment.uploaded_at:type_name -> google.protobuf.Timestamp
+	32, // 2: product.ProductId.as_of:type_name -> google.protobuf.Timestamp
+	0,  // 3: product.ProductRes1.product:type_name -> product.Product
+	0,  // 4: product.ProductResN.products:type_name -> product.Product
+	0,  // 5: product.ProductChunk.products:type_name -> product.Product
+	10, // 6: product.PriceHistogramRes.buckets:type_name -> product.PriceBucket
+	13, // 7: product.CountByRes.groups:type_name -> product.GroupCount
+	32, // 8: product.WebhookSubscription.created_at:type_name -> google.protobuf.Timestamp
+	32, // 9: product.WebhookSubscription.updated_at:type_name -> google.protobuf.Timestamp
+	15, // 10: product.WebhookSubscriptionList.subscriptions:type_name -> product.WebhookSubscription
+	32, // 11: product.DeliveryAttempt.at:type_name -> google.protobuf.Timestamp
+	32, // 12: product.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	19, // 13: product.WebhookDelivery.history:type_name -> product.DeliveryAttempt
+	32, // 14: product.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	32, // 15: product.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	20, // 16: product.WebhookDeliveryList.deliveries:type_name -> product.WebhookDelivery
+	32, // 17: product.PriceSchedule.starts_at:type_name -> google.protobuf.Timestamp
+	32, // 18: product.PriceSchedule.ends_at:type_name -> google.protobuf.Timestamp
+	32, // 19: product.PriceSchedule.created_at:type_name -> google.protobuf.Timestamp
+	32, // 20: product.PriceSchedule.applied_at:type_name -> google.protobuf.Timestamp
+	32, // 21: product.PriceSchedule.completed_at:type_name -> google.protobuf.Timestamp
+	22, // 22: product.PriceScheduleList.schedules:type_name -> product.PriceSchedule
+	32, // 23: product.PriceHistoryReq.from:type_name -> google.protobuf.Timestamp
+	32, // 24: product.PriceHistoryReq.to:type_name -> google.protobuf.Timestamp
+	32, // 25: product.PriceChange.effective_from:type_name -> google.protobuf.Timestamp
+	32, // 26: product.PriceChange.effective_to:type_name -> google.protobuf.Timestamp
+	26, // 27: product.PriceHistory.changes:type_name -> product.PriceChange
+	32, // 28: product.ApiKey.expires_at:type_name -> google.protobuf.Timestamp
+	32, // 29: product.ApiKey.last_used_at:type_name -> google.protobuf.Timestamp
+	32, // 30: product.ApiKey.revoked_at:type_name -> google.protobuf.Timestamp
+	32, // 31: product.ApiKey.rotated_at:type_name -> google.protobuf.Timestamp
+	32, // 32: product.ApiKey.created_at:type_name -> google.protobuf.Timestamp
+	28, // 33: product.ApiKeyList.keys:type_name -> product.ApiKey
+	33, // 34: product.ProductService.GetAll:input_type -> google.protobuf.Empty
+	2,  // 35: product.ProductService.GetByID:input_type -> product.ProductId
+	0,  // 36: product.ProductService.Create:input_type -> product.Product
+	0,  // 37: product.ProductService.Update:input_type -> product.Product
+	2,  // 38: product.ProductService.Delete:input_type -> product.ProductId
+	5,  // 39: product.ProductService.StreamProducts:input_type -> product.StreamProductsReq
+	33, // 40: product.ReportService.InventoryValue:input_type -> google.protobuf.Empty
+	8,  // 41: product.ReportService.LowStock:input_type -> product.LowStockReq
+	9,  // 42: product.ReportService.PriceHistogram:input_type -> product.PriceHistogramReq
+	12, // 43: product.ReportService.CountBy:input_type -> product.CountByReq
+	15, // 44: product.WebhookService.CreateSubscription:input_type -> product.WebhookSubscription
+	16, // 45: product.WebhookService.GetSubscription:input_type -> product.WebhookId
+	33, // 46: product.WebhookService.ListSubscriptions:input_type -> google.protobuf.Empty
+	15, // 47: product.WebhookService.UpdateSubscription:input_type -> product.WebhookSubscription
+	16, // 48: product.WebhookService.DeleteSubscription:input_type -> product.WebhookId
+	18, // 49: product.WebhookService.ListDeliveries:input_type -> product.ListDeliveriesReq
+	16, // 50: product.WebhookService.RetryDelivery:input_type -> product.WebhookId
+	22, // 51: product.PriceService.CreateSchedule:input_type -> product.PriceSchedule
+	2,  // 52: product.PriceService.ListSchedules:input_type -> product.ProductId
+	23, // 53: product.PriceService.CancelSchedule:input_type -> product.PriceScheduleId
+	25, // 54: product.PriceService.GetHistory:input_type -> product.PriceHistoryReq
+	28, // 55: product.ApiKeyService.CreateKey:input_type -> product.ApiKey
+	33, // 56: product.ApiKeyService.ListKeys:input_type -> google.protobuf.Empty
+	29, // 57: product.ApiKeyService.GetKey:input_type -> product.ApiKeyId
+	30, // 58: product.ApiKeyService.RotateKey:input_type -> product.RotateApiKeyReq
+	29, // 59: product.ApiKeyService.RevokeKey:input_type -> product.ApiKeyId
+	4,  // 60: product.ProductService.GetAll:output_type -> product.ProductResN
+	3,  // 61: product.ProductService.GetByID:output_type -> product.ProductRes1
+	3,  // 62: product.ProductService.Create:output_type -> product.ProductRes1
+	3,  // 63: product.ProductService.Update:output_type -> product.ProductRes1
+	33, // 64: product.ProductService.Delete:output_type -> google.protobuf.Empty
+	6,  // 65: product.ProductService.StreamProducts:output_type -> product.ProductChunk
+	7,  // 66: product.ReportService.InventoryValue:output_type -> product.InventoryValueRes
+	4,  // 67: product.ReportService.LowStock:output_type -> product.ProductResN
+	11, // 68: product.ReportService.PriceHistogram:output_type -> product.PriceHistogramRes
+	14, // 69: product.ReportService.CountBy:output_type -> product.CountByRes
+	15, // 70: product.WebhookService.CreateSubscription:output_type -> product.WebhookSubscription
+	15, // 71: product.WebhookService.GetSubscription:output_type -> product.WebhookSubscription
+	17, // 72: product.WebhookService.ListSubscriptions:output_type -> product.WebhookSubscriptionList
+	15, // 73: product.WebhookService.UpdateSubscription:output_type -> product.WebhookSubscription
+	33, // 74: product.WebhookService.DeleteSubscription:output_type -> google.protobuf.Empty
+	21, // 75: product.WebhookService.ListDeliveries:output_type -> product.WebhookDeliveryList
+	20, // 76: product.WebhookService.RetryDelivery:output_type -> product.WebhookDelivery
+	22, // 77: product.PriceService.CreateSchedule:output_type -> product.PriceSchedule
+	24, // 78: product.PriceService.ListSchedules:output_type -> product.PriceScheduleList
+	22, // 79: product.PriceService.CancelSchedule:output_type -> product.PriceSchedule
+	27, // 80: product.PriceService.GetHistory:output_type -> product.PriceHistory
+	28, // 81: product.ApiKeyService.CreateKey:output_type -> product.ApiKey
+	31, // 82: product.ApiKeyService.ListKeys:output_type -> product.ApiKeyList
+	28, // 83: product.ApiKeyService.GetKey:output_type -> product.ApiKey
+	28, // 84: product.ApiKeyService.RotateKey:output_type -> product.ApiKey
+	28, // 85: product.ApiKeyService.RevokeKey:output_type -> product.ApiKey
+	60, // [60:86] is the sub-list for method output_type
+	34, // [34:60] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_product_proto_init() }
@@ -308,15 +2419,18 @@ func file_product_proto_init() {
 	if File_product_proto != nil {
 		return
 	}
+	file_product_proto_msgTypes[8].OneofWrappers = []any{}
+	file_product_proto_msgTypes[10].OneofWrappers = []any{}
+	file_product_proto_msgTypes[22].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_product_proto_rawDesc), len(file_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   32,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   5,
 		},
 		GoTypes:           file_product_proto_goTypes,
 		DependencyIndexes: file_product_proto_depIdxs,
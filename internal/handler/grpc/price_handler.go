@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type PriceGRPCHandler struct {
+	pb.UnimplementedPriceServiceServer
+	Service *service.PriceService
+}
+
+var GrpcPriceHandlerTracer = otel.Tracer("GrpcPriceHandler")
+
+func NewPriceGRPCHandler(svc *service.PriceService) *PriceGRPCHandler {
+	return &PriceGRPCHandler{
+		Service: svc,
+	}
+}
+
+func (h *PriceGRPCHandler) CreateSchedule(ctx context.Context, req *pb.PriceSchedule) (*pb.PriceSchedule, error) {
+	ctx, span := GrpcPriceHandlerTracer.Start(ctx, "GrpcPriceHandler.CreateSchedule")
+	defer span.End()
+	logger.Info(ctx, "GrpcPriceHandler.CreateSchedule")
+
+	sched := &model.PriceSchedule{
+		Price:    req.GetPrice(),
+		StartsAt: timeOrZero(req.GetStartsAt()),
+	}
+	if req.GetEndsAt() != nil {
+		end := req.GetEndsAt().AsTime()
+		sched.EndsAt = &end
+	}
+	created, err := h.Service.CreateSchedule(ctx, req.GetProductId(), sched)
+	if err != nil {
+		return nil, priceError(err)
+	}
+	return toProtoSchedule(created), nil
+}
+
+func (h *PriceGRPCHandler) ListSchedules(ctx context.Context, req *pb.ProductId) (*pb.PriceScheduleList, error) {
+	ctx, span := GrpcPriceHandlerTracer.Start(ctx, "GrpcPriceHandler.ListSchedules")
+	defer span.End()
+	logger.Info(ctx, "GrpcPriceHandler.ListSchedules")
+
+	schedules, err := h.Service.GetSchedules(ctx, req.GetId())
+	if err != nil {
+		return nil, priceError(err)
+	}
+	res := &pb.PriceScheduleList{}
+	for i := range schedules {
+		res.Schedules = append(res.Schedules, toProtoSchedule(&schedules[i]))
+	}
+	return res, nil
+}
+
+func (h *PriceGRPCHandler) CancelSchedule(ctx context.Context, req *pb.PriceScheduleId) (*pb.PriceSchedule, error) {
+	ctx, span := GrpcPriceHandlerTracer.Start(ctx, "GrpcPriceHandler.CancelSchedule")
+	defer span.End()
+	logger.Info(ctx, "GrpcPriceHandler.CancelSchedule")
+
+	sched, err := h.Service.CancelSchedule(ctx, req.GetId())
+	if err != nil {
+		return nil, priceError(err)
+	}
+	return toProtoSchedule(sched), nil
+}
+
+func (h *PriceGRPCHandler) GetHistory(ctx context.Context, req *pb.PriceHistoryReq) (*pb.PriceHistory, error) {
+	ctx, span := GrpcPriceHandlerTracer.Start(ctx, "GrpcPriceHandler.GetHistory")
+	defer span.End()
+	logger.Info(ctx, "GrpcPriceHandler.GetHistory")
+
+	changes, err := h.Service.GetHistory(ctx, req.GetProductId(), timeOrZero(req.GetFrom()), timeOrZero(req.GetTo()))
+	if err != nil {
+		return nil, priceError(err)
+	}
+	res := &pb.PriceHistory{}
+	for _, c := range changes {
+		change := &pb.PriceChange{
+			Id:            c.ID.Hex(),
+			ProductId:     c.ProductID.Hex(),
+			Price:         c.Price,
+			EffectiveFrom: timestamppb.New(c.EffectiveFrom),
+			EffectiveTo:   timestampOrNil(c.EffectiveTo),
+			Source:        c.Source,
+		}
+		if c.ScheduleID != nil {
+			change.ScheduleId = c.ScheduleID.Hex()
+		}
+		res.Changes = append(res.Changes, change)
+	}
+	return res, nil
+}
+
+func toProtoSchedule(s *model.PriceSchedule) *pb.PriceSchedule {
+	return &pb.PriceSchedule{
+		Id:            s.ID.Hex(),
+		ProductId:     s.ProductID.Hex(),
+		Price:         s.Price,
+		StartsAt:      timestamppb.New(s.StartsAt),
+		EndsAt:        timestampOrNil(s.EndsAt),
+		Status:        s.Status,
+		PreviousPrice: s.PreviousPrice,
+		CreatedAt:     timestamppb.New(s.CreatedAt),
+		AppliedAt:     timestampOrNil(s.AppliedAt),
+		CompletedAt:   timestampOrNil(s.CompletedAt),
+	}
+}
+
+// timeOrZero maps an unset timestamp to the zero time, which the service
+// treats as "no bound".
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func priceError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidSchedule):
+		return invalidArgument(err)
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrScheduleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrScheduleConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
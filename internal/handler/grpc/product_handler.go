@@ -2,7 +2,14 @@ package grpc
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
 
 	pb "simple-crud/internal/handler/grpc/pb"
 	"simple-crud/internal/logger"
@@ -11,7 +18,13 @@ import (
 	"simple-crud/internal/utils"
 
 	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ProductGRPCHandler struct {
@@ -32,72 +45,133 @@ func (h *ProductGRPCHandler) GetAll(ctx context.Context, _ *emptypb.Empty) (*pb.
 	defer span.End()
 	logger.Info(ctx, "GrpcProductHandler.GetAll")
 
-	products, err := h.Service.GetAll(ctx)
+	etag, modified, err := h.CatalogValidators(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	var protoProducts []*pb.Product
-	for _, p := range products {
-		protoProducts = append(protoProducts, &pb.Product{
-			Id:    p.ID.Hex(),
-			Name:  p.Name,
-			Price: p.Price,
-			Stock: int32(p.Stock),
-		})
+	products, err := h.Service.GetAll(ctx)
+	if err != nil {
+		return nil, productError(ctx, err)
 	}
+	setValidators(ctx, etag, modified)
 
 	return &pb.ProductResN{
 		Resolver: utils.GetHost(),
-		Products: protoProducts,
+		Products: toProtoProducts(products),
 	}, nil
 }
 
+// StreamProducts sends the catalog in chunks instead of a single ProductResN,
+// so large catalogs stay under gRPC's message size limit. Send blocks while
+// the client's flow-control window is full, and the stream context is
+// cancelled when the client goes away, which also stops the Mongo cursor.
+func (h *ProductGRPCHandler) StreamProducts(req *pb.StreamProductsReq, stream grpc.ServerStreamingServer[pb.ProductChunk]) error {
+	ctx, span := GrpcProductHandlerTracer.Start(stream.Context(), "GrpcProductHandler.StreamProducts")
+	defer span.End()
+	logger.Info(ctx, "GrpcProductHandler.StreamProducts")
+
+	var seq int64
+	err := h.Service.Stream(ctx, int(req.GetChunkSize()), func(products []model.Product) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		seq++
+		return stream.Send(&pb.ProductChunk{
+			Resolver: utils.GetHost(),
+			Products: toProtoProducts(products),
+			Seq:      seq,
+		})
+	})
+	if err != nil && ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
 func (h *ProductGRPCHandler) GetByID(ctx context.Context, req *pb.ProductId) (*pb.ProductRes1, error) {
 	ctx, span := GrpcProductHandlerTracer.Start(ctx, "GrpcProductHandler.GetByID")
 	defer span.End()
 	logger.Info(ctx, "GrpcProductHandler.GetByID")
 
-	product, err := h.Service.GetByID(ctx, req.GetId())
+	var product *model.Product
+	var err error
+	if req.GetAsOf() != nil {
+		product, err = h.Service.GetByIDAsOf(ctx, req.GetId(), req.GetAsOf().AsTime())
+	} else {
+		product, err = h.Service.GetByID(ctx, req.GetId())
+	}
 	if err != nil {
-		return nil, err
+		return nil, productError(ctx, err)
 	}
 
+	res := toProtoProduct(product)
+	setValidators(ctx, productETag(res), product.UpdatedAt)
 	return &pb.ProductRes1{
 		Resolver: utils.GetHost(),
-		Product: &pb.Product{
-			Id:    product.ID.Hex(),
-			Name:  product.Name,
-			Price: product.Price,
-			Stock: int32(product.Stock),
-		},
+		Product:  res,
 	}, nil
 }
 
+// CatalogValidators returns the ETag and Last-Modified GetAll would answer
+// with now, from the catalog's change marker rather than the catalog; the
+// HTTP gateway uses it to answer conditional GETs of /products.
+func (h *ProductGRPCHandler) CatalogValidators(ctx context.Context) (string, time.Time, error) {
+	marker, err := h.Service.ChangeMarker(ctx)
+	if err != nil {
+		return "", time.Time{}, productError(ctx, err)
+	}
+	if marker.Version == 0 {
+		return `"c0"`, time.Time{}, nil
+	}
+	etag := fmt.Sprintf(`"c%d-%s"`, marker.Version, strconv.FormatInt(marker.UpdatedAt.UnixMilli(), 36))
+	return etag, marker.UpdatedAt, nil
+}
+
+// productETag is a strong ETag hashing the product's content.
+func productETag(p *pb.Product) string {
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(p)
+	sum := sha256.Sum256(b)
+	return `"p` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setValidators sends the response's ETag and Last-Modified as metadata;
+// the HTTP gateway forwards them as headers and answers conditional
+// requests with them.
+func setValidators(ctx context.Context, etag string, modified time.Time) {
+	md := metadata.Pairs("etag", etag)
+	if !modified.IsZero() {
+		md.Append("last-modified", modified.UTC().Format(http.TimeFormat))
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
+
 func (h *ProductGRPCHandler) Create(ctx context.Context, req *pb.Product) (*pb.ProductRes1, error) {
 	ctx, span := GrpcProductHandlerTracer.Start(ctx, "GrpcProductHandler.Create")
 	defer span.End()
 	logger.Info(ctx, "GrpcProductHandler.Create")
 
 	product := &model.Product{
-		Name:  req.GetName(),
-		Price: req.GetPrice(),
-		Stock: int(req.GetStock()),
+		Name:     req.GetName(),
+		Price:    req.GetPrice(),
+		Stock:    int(req.GetStock()),
+		Category: req.GetCategory(),
+		Tags:     req.GetTags(),
 	}
 
 	created, err := h.Service.Create(ctx, product)
 	if err != nil {
-		return nil, err
+		return nil, productError(ctx, err)
 	}
 
 	return &pb.ProductRes1{
 		Resolver: utils.GetHost(),
-		Product: &pb.Product{
-			Id:    created.ID.Hex(),
-			Name:  created.Name,
-			Price: created.Price,
-			Stock: int32(created.Stock),
-		},
+		Product:  toProtoProduct(created),
 	}, nil
 }
 
@@ -107,23 +181,25 @@ func (h *ProductGRPCHandler) Update(ctx context.Context, req *pb.Product) (*pb.P
 	logger.Info(ctx, "GrpcProductHandler.Update")
 
 	if req.GetId() == "" {
-		return nil, errors.New("id is required")
+		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
 	p := model.Product{
-		Name:  req.GetName(),
-		Price: req.GetPrice(),
-		Stock: int(req.GetStock()),
+		Name:     req.GetName(),
+		Price:    req.GetPrice(),
+		Stock:    int(req.GetStock()),
+		Category: req.GetCategory(),
+		Tags:     req.GetTags(),
 	}
 
-	err := h.Service.Update(ctx, req.GetId(), &p)
+	updated, err := h.Service.Update(ctx, req.GetId(), &p)
 	if err != nil {
-		return nil, err
+		return nil, productError(ctx, err)
 	}
 
 	return &pb.ProductRes1{
 		Resolver: utils.GetHost(),
-		Product:  req,
+		Product:  toProtoProduct(updated),
 	}, nil
 }
 
@@ -133,7 +209,58 @@ func (h *ProductGRPCHandler) Delete(ctx context.Context, req *pb.ProductId) (*em
 	logger.Info(ctx, "GrpcProductHandler.Delete")
 
 	if err := h.Service.Delete(ctx, req.GetId()); err != nil {
-		return nil, err
+		return nil, productError(ctx, err)
 	}
 	return &emptypb.Empty{}, nil
 }
+
+// productError maps service errors to gRPC statuses. Unexpected errors are
+// logged with the call's trace and answered with a fixed message, so
+// driver and database details never reach the client.
+func productError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidProduct), errors.Is(err, service.ErrInvalidProductID):
+		return invalidArgument(err)
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrNoPriceAsOf):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	default:
+		logger.Error(ctx, "Product call failed",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", err)),
+		)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func toProtoProduct(p *model.Product) *pb.Product {
+	res := &pb.Product{
+		Id:       p.ID.Hex(),
+		Name:     p.Name,
+		Price:    p.Price,
+		Stock:    int32(p.Stock),
+		Category: p.Category,
+		Tags:     p.Tags,
+	}
+	for _, a := range p.Attachments {
+		res.Attachments = append(res.Attachments, &pb.Attachment{
+			Id:          a.ID,
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			UploadedAt:  timestamppb.New(a.UploadedAt),
+		})
+	}
+	return res
+}
+
+func toProtoProducts(products []model.Product) []*pb.Product {
+	protoProducts := make([]*pb.Product, 0, len(products))
+	for i := range products {
+		protoProducts = append(protoProducts, toProtoProduct(&products[i]))
+	}
+	return protoProducts
+}
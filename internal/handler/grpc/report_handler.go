@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/service"
+	"simple-crud/internal/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+type ReportGRPCHandler struct {
+	pb.UnimplementedReportServiceServer
+	Service *service.ReportService
+}
+
+var GrpcReportHandlerTracer = otel.Tracer("GrpcReportHandler")
+
+func NewReportGRPCHandler(svc *service.ReportService) *ReportGRPCHandler {
+	return &ReportGRPCHandler{
+		Service: svc,
+	}
+}
+
+func (h *ReportGRPCHandler) InventoryValue(ctx context.Context, _ *emptypb.Empty) (*pb.InventoryValueRes, error) {
+	ctx, span := GrpcReportHandlerTracer.Start(ctx, "GrpcReportHandler.InventoryValue")
+	defer span.End()
+	logger.Info(ctx, "GrpcReportHandler.InventoryValue")
+
+	result, err := h.Service.InventoryValue(ctx)
+	if err != nil {
+		return nil, reportError(err)
+	}
+	h.setCacheControl(ctx)
+
+	return &pb.InventoryValueRes{
+		Resolver:     utils.GetHost(),
+		TotalValue:   result.TotalValue,
+		TotalStock:   result.TotalStock,
+		ProductCount: result.ProductCount,
+	}, nil
+}
+
+func (h *ReportGRPCHandler) LowStock(ctx context.Context, req *pb.LowStockReq) (*pb.ProductResN, error) {
+	ctx, span := GrpcReportHandlerTracer.Start(ctx, "GrpcReportHandler.LowStock")
+	defer span.End()
+	logger.Info(ctx, "GrpcReportHandler.LowStock")
+
+	threshold := service.DefaultLowStockThreshold
+	if req.Threshold != nil {
+		threshold = int(req.GetThreshold())
+	}
+	products, err := h.Service.LowStock(ctx, threshold, req.GetLimit())
+	if err != nil {
+		return nil, reportError(err)
+	}
+	h.setCacheControl(ctx)
+
+	return &pb.ProductResN{
+		Resolver: utils.GetHost(),
+		Products: toProtoProducts(products),
+	}, nil
+}
+
+func (h *ReportGRPCHandler) PriceHistogram(ctx context.Context, req *pb.PriceHistogramReq) (*pb.PriceHistogramRes, error) {
+	ctx, span := GrpcReportHandlerTracer.Start(ctx, "GrpcReportHandler.PriceHistogram")
+	defer span.End()
+	logger.Info(ctx, "GrpcReportHandler.PriceHistogram")
+
+	buckets, err := h.Service.PriceHistogram(ctx, req.GetBoundaries(), int(req.GetBuckets()))
+	if err != nil {
+		return nil, reportError(err)
+	}
+	h.setCacheControl(ctx)
+
+	res := &pb.PriceHistogramRes{Resolver: utils.GetHost()}
+	for _, b := range buckets {
+		res.Buckets = append(res.Buckets, &pb.PriceBucket{Min: b.Min, Max: b.Max, Count: b.Count})
+	}
+	return res, nil
+}
+
+func (h *ReportGRPCHandler) CountBy(ctx context.Context, req *pb.CountByReq) (*pb.CountByRes, error) {
+	ctx, span := GrpcReportHandlerTracer.Start(ctx, "GrpcReportHandler.CountBy")
+	defer span.End()
+	logger.Info(ctx, "GrpcReportHandler.CountBy")
+
+	by := req.GetBy()
+	if by == "" {
+		by = service.DefaultCountBy
+	}
+	groups, err := h.Service.CountBy(ctx, by)
+	if err != nil {
+		return nil, reportError(err)
+	}
+	h.setCacheControl(ctx)
+
+	res := &pb.CountByRes{Resolver: utils.GetHost(), By: by}
+	for _, g := range groups {
+		res.Groups = append(res.Groups, &pb.GroupCount{Key: g.Key, Count: g.Count, Stock: g.Stock, Value: g.Value})
+	}
+	return res, nil
+}
+
+// setCacheControl sends the report cache lifetime as response metadata; the
+// HTTP gateway forwards it as the Cache-Control header.
+func (h *ReportGRPCHandler) setCacheControl(ctx context.Context) {
+	value := "no-store"
+	if ttl := int(h.Service.CacheTTL().Seconds()); ttl > 0 {
+		value = "private, max-age=" + strconv.Itoa(ttl)
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("cache-control", value))
+}
+
+func reportError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidReportParams):
+		return invalidArgument(err)
+	case errors.Is(err, context.DeadlineExceeded), mongo.IsTimeout(err):
+		return status.Error(codes.DeadlineExceeded, "report timed out")
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhookGRPCHandler struct {
+	pb.UnimplementedWebhookServiceServer
+	Service *service.WebhookService
+}
+
+var GrpcWebhookHandlerTracer = otel.Tracer("GrpcWebhookHandler")
+
+func NewWebhookGRPCHandler(svc *service.WebhookService) *WebhookGRPCHandler {
+	return &WebhookGRPCHandler{
+		Service: svc,
+	}
+}
+
+func (h *WebhookGRPCHandler) CreateSubscription(ctx context.Context, req *pb.WebhookSubscription) (*pb.WebhookSubscription, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.CreateSubscription")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.CreateSubscription")
+
+	created, err := h.Service.Create(ctx, fromProtoSubscription(req))
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return toProtoSubscription(created), nil
+}
+
+func (h *WebhookGRPCHandler) GetSubscription(ctx context.Context, req *pb.WebhookId) (*pb.WebhookSubscription, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.GetSubscription")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.GetSubscription")
+
+	sub, err := h.Service.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return toProtoSubscription(sub), nil
+}
+
+func (h *WebhookGRPCHandler) ListSubscriptions(ctx context.Context, _ *emptypb.Empty) (*pb.WebhookSubscriptionList, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.ListSubscriptions")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.ListSubscriptions")
+
+	subs, err := h.Service.GetAll(ctx)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	res := &pb.WebhookSubscriptionList{}
+	for i := range subs {
+		res.Subscriptions = append(res.Subscriptions, toProtoSubscription(&subs[i]))
+	}
+	return res, nil
+}
+
+func (h *WebhookGRPCHandler) UpdateSubscription(ctx context.Context, req *pb.WebhookSubscription) (*pb.WebhookSubscription, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.UpdateSubscription")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.UpdateSubscription")
+
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	updated, err := h.Service.Update(ctx, req.GetId(), fromProtoSubscription(req))
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return toProtoSubscription(updated), nil
+}
+
+func (h *WebhookGRPCHandler) DeleteSubscription(ctx context.Context, req *pb.WebhookId) (*emptypb.Empty, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.DeleteSubscription")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.DeleteSubscription")
+
+	if err := h.Service.Delete(ctx, req.GetId()); err != nil {
+		return nil, webhookError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *WebhookGRPCHandler) ListDeliveries(ctx context.Context, req *pb.ListDeliveriesReq) (*pb.WebhookDeliveryList, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.ListDeliveries")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.ListDeliveries")
+
+	deliveries, err := h.Service.GetDeliveries(ctx, req.GetSubscriptionId(), req.GetStatus(), req.GetLimit())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	res := &pb.WebhookDeliveryList{}
+	for i := range deliveries {
+		res.Deliveries = append(res.Deliveries, toProtoDelivery(&deliveries[i]))
+	}
+	return res, nil
+}
+
+func (h *WebhookGRPCHandler) RetryDelivery(ctx context.Context, req *pb.WebhookId) (*pb.WebhookDelivery, error) {
+	ctx, span := GrpcWebhookHandlerTracer.Start(ctx, "GrpcWebhookHandler.RetryDelivery")
+	defer span.End()
+	logger.Info(ctx, "GrpcWebhookHandler.RetryDelivery")
+
+	delivery, err := h.Service.RetryDelivery(ctx, req.GetId())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return toProtoDelivery(delivery), nil
+}
+
+func fromProtoSubscription(req *pb.WebhookSubscription) *model.WebhookSubscription {
+	return &model.WebhookSubscription{
+		URL:      req.GetUrl(),
+		Events:   req.GetEvents(),
+		Secret:   req.GetSecret(),
+		Disabled: req.GetDisabled(),
+	}
+}
+
+func toProtoSubscription(sub *model.WebhookSubscription) *pb.WebhookSubscription {
+	return &pb.WebhookSubscription{
+		Id:        sub.ID.Hex(),
+		Url:       sub.URL,
+		Events:    sub.Events,
+		Secret:    sub.Secret,
+		Disabled:  sub.Disabled,
+		CreatedAt: timestamppb.New(sub.CreatedAt),
+		UpdatedAt: timestamppb.New(sub.UpdatedAt),
+	}
+}
+
+func toProtoDelivery(d *model.WebhookDelivery) *pb.WebhookDelivery {
+	res := &pb.WebhookDelivery{
+		Id:             d.ID.Hex(),
+		SubscriptionId: d.SubscriptionID.Hex(),
+		Event:          d.Event,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       int32(d.Attempts),
+		NextAttemptAt:  timestamppb.New(d.NextAttemptAt),
+		LastError:      d.LastError,
+		CreatedAt:      timestamppb.New(d.CreatedAt),
+	}
+	if d.DeliveredAt != nil {
+		res.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+	for _, a := range d.History {
+		res.History = append(res.History, &pb.DeliveryAttempt{
+			At:         timestamppb.New(a.At),
+			StatusCode: int32(a.StatusCode),
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+		})
+	}
+	return res
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhook):
+		return invalidArgument(err)
+	case errors.Is(err, service.ErrWebhookNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
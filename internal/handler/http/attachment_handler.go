@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/problem"
+	"simple-crud/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// multipartOverhead is the allowance for multipart headers and boundaries on
+// top of the attachment size limit.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	service *service.AttachmentService
+}
+
+var HttpAttachmentHandlerTracer = otel.Tracer("HttpAttachmentHandler")
+
+func NewAttachmentHandler(service *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+	}
+}
+
+// Upload stores every file part of a multipart/form-data body as an
+// attachment of the product given by ?id=.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	parentCtx := r.Context()
+
+	// Start span with extracted context
+	// Extract context from incoming headers (traceparent, etc.)
+	propCtx := otel.GetTextMapPropagator().Extract(parentCtx, propagation.HeaderCarrier(r.Header))
+	ctx, span := HttpAttachmentHandlerTracer.Start(propCtx, "HttpAttachmentHandler.Upload")
+	defer span.End()
+	logger.Info(ctx, "HttpAttachmentHandler.Upload")
+
+	id := pathParam(r, "id")
+	if id == "" {
+		problem.Error(w, r, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxBytes()+multipartOverhead)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		problem.Error(w, r, "Expected a multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	uploaded := []model.Attachment{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeAttachmentError(w, r, err)
+			return
+		}
+		if part.FileName() == "" {
+			_ = part.Close()
+			continue
+		}
+		a, err := h.service.Upload(ctx, id, part.FileName(), part.Header.Get("Content-Type"), part)
+		_ = part.Close()
+		if err != nil {
+			writeAttachmentError(w, r, err)
+			return
+		}
+		uploaded = append(uploaded, *a)
+	}
+	if len(uploaded) == 0 {
+		problem.Error(w, r, "No file parts in request", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(uploaded)
+}
+
+// Download serves the attachment given by ?file= with range support. Without
+// ?file= it lists the product's attachments.
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	parentCtx := r.Context()
+
+	// Start span with extracted context
+	// Extract context from incoming headers (traceparent, etc.)
+	propCtx := otel.GetTextMapPropagator().Extract(parentCtx, propagation.HeaderCarrier(r.Header))
+	ctx, span := HttpAttachmentHandlerTracer.Start(propCtx, "HttpAttachmentHandler.Download")
+	defer span.End()
+	logger.Info(ctx, "HttpAttachmentHandler.Download")
+
+	id := pathParam(r, "id")
+	if id == "" {
+		problem.Error(w, r, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	file := pathParam(r, "file")
+	if file == "" {
+		attachments, err := h.service.List(ctx, id)
+		if err != nil {
+			writeAttachmentError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(attachments)
+		return
+	}
+
+	a, blob, err := h.service.Open(ctx, id, file)
+	if err != nil {
+		writeAttachmentError(w, r, err)
+		return
+	}
+	defer blob.Close()
+
+	// Attachments are immutable, so the ID is a strong validator for If-Range.
+	disposition := "attachment"
+	if strings.HasPrefix(a.ContentType, "image/") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+a.ID+`"`)
+	http.ServeContent(w, r.WithContext(ctx), a.Filename, a.UploadedAt, blob)
+}
+
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	parentCtx := r.Context()
+
+	// Start span with extracted context
+	// Extract context from incoming headers (traceparent, etc.)
+	propCtx := otel.GetTextMapPropagator().Extract(parentCtx, propagation.HeaderCarrier(r.Header))
+	ctx, span := HttpAttachmentHandlerTracer.Start(propCtx, "HttpAttachmentHandler.Delete")
+	defer span.End()
+	logger.Info(ctx, "HttpAttachmentHandler.Delete")
+
+	id := pathParam(r, "id")
+	file := pathParam(r, "file")
+	if id == "" || file == "" {
+		problem.Error(w, r, "ID and file are required", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Delete(ctx, id, file); err != nil {
+		writeAttachmentError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAttachmentError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, service.ErrInvalidAttachment):
+		problem.Error(w, r, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrAttachmentNotFound):
+		problem.Error(w, r, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrAttachmentTooLarge), errors.As(err, &maxBytesErr):
+		problem.Error(w, r, service.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrAttachmentType):
+		problem.Error(w, r, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, service.ErrForbidden):
+		problem.Error(w, r, err.Error(), http.StatusForbidden)
+	default:
+		problem.Error(w, r, "Failed to process attachment", http.StatusInternalServerError)
+	}
+}
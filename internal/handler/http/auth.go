@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/problem"
+)
+
+// RequireAuth wraps every non-public route so it answers 401 unless the
+// request carries valid credentials (see middleware_http.AuthMiddleware,
+// which verifies them), and 403 unless they grant the route's scope.
+func RequireAuth(routes []Route) []Route {
+	out := make([]Route, len(routes))
+	for i, rt := range routes {
+		if !rt.Public {
+			rt.Handler = authenticated(requiredScope(rt), rt.Handler)
+		}
+		out[i] = rt
+	}
+	return out
+}
+
+// requiredScope is admin for webhooks and API key management, and
+// products:read or products:write elsewhere depending on the method.
+func requiredScope(rt Route) string {
+	switch {
+	case strings.HasPrefix(rt.Path, "/admin/"), strings.HasPrefix(rt.Path, "/webhook"):
+		return auth.ScopeAdmin
+	case rt.Method == http.MethodGet || rt.Method == http.MethodHead:
+		return auth.ScopeProductsRead
+	default:
+		return auth.ScopeProductsWrite
+	}
+}
+
+func authenticated(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := auth.FromContext(r.Context())
+		if !ok {
+			unauthenticated(w, r)
+			return
+		}
+		if !p.HasScope(scope) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="simple-crud", error="insufficient_scope", scope="`+scope+`"`)
+			problem.Error(w, r, "credentials lack the "+scope+" scope", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func unauthenticated(w http.ResponseWriter, r *http.Request) {
+	err := auth.ErrorFromContext(r.Context())
+	switch {
+	case errors.Is(err, auth.ErrMissingToken):
+		// RFC 6750: no error code when credentials are missing.
+		w.Header().Set("WWW-Authenticate", `Bearer realm="simple-crud"`)
+		problem.Error(w, r, auth.ErrMissingToken.Error(), http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrInvalidToken):
+		// Why they were rejected is not disclosed.
+		w.Header().Set("WWW-Authenticate", `Bearer realm="simple-crud", error="invalid_token"`)
+		problem.Error(w, r, auth.ErrInvalidToken.Error(), http.StatusUnauthorized)
+	default:
+		// The credentials could not be checked, e.g. the key store is down.
+		problem.Error(w, r, "credentials could not be verified", http.StatusServiceUnavailable)
+	}
+}
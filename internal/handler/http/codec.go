@@ -0,0 +1,511 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"simple-crud/internal/problem"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Media types the gateway routes read and write.
+const (
+	MIMEJSON     = "application/json"
+	MIMEProtobuf = "application/x-protobuf"
+	MIMENDJSON   = "application/x-ndjson"
+	MIMECSV      = "text/csv"
+	MIMEMsgpack  = "application/msgpack"
+)
+
+// mediaTypes is in server preference order; the first one answers "*/*" and
+// requests without an Accept header.
+var mediaTypes = []string{MIMEJSON, MIMEProtobuf, MIMENDJSON, MIMECSV, MIMEMsgpack}
+
+// csvListSeparator joins repeated scalar fields inside one CSV cell.
+const csvListSeparator = "|"
+
+var jsonMarshaler = &strictJSON{JSONPb: &runtime.JSONPb{
+	MarshalOptions: protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	},
+}}
+
+func marshalerOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonMarshaler),
+		runtime.WithMarshalerOption(MIMEJSON, jsonMarshaler),
+		runtime.WithMarshalerOption(MIMEProtobuf, &protobufMarshaler{}),
+		runtime.WithMarshalerOption(MIMENDJSON, &ndjsonMarshaler{strictJSON: jsonMarshaler}),
+		runtime.WithMarshalerOption(MIMECSV, &csvMarshaler{}),
+		runtime.WithMarshalerOption(MIMEMsgpack, &msgpackMarshaler{}),
+		runtime.WithForwardResponseRewriter(wholeProtobufResponse),
+	}
+}
+
+type mediaTypeKey struct{}
+
+// negotiate picks the response encoding from Accept (406 when none of
+// mediaTypes is acceptable) and rejects request bodies without a
+// Content-Type or in an encoding the gateway cannot read (415). The gateway
+// then selects its marshalers from the normalised headers.
+func negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		accept, ok := acceptable(r.Header.Values("Accept"))
+		if !ok {
+			problem.Error(w, r, "none of the accepted media types is supported: "+
+				strings.Join(mediaTypes, ", "), http.StatusNotAcceptable)
+			return
+		}
+		if detail := checkContentType(r); detail != "" {
+			problem.Error(w, r, detail, http.StatusUnsupportedMediaType)
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), mediaTypeKey{}, accept))
+		r.Header.Set("Accept", accept)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptable returns the supported media type the client prefers most.
+// Ties keep header order; wildcards resolve in mediaTypes order.
+func acceptable(header []string) (string, bool) {
+	type ranged struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []ranged
+	for _, h := range header {
+		for _, part := range strings.Split(h, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			mt, params, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if q > 0 {
+				ranges = append(ranges, ranged{mt, q})
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		// No Accept header means any media type.
+		return mediaTypes[0], !hasEntries(header)
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	for _, rg := range ranges {
+		for _, mt := range mediaTypes {
+			if rg.mediaType == mt || rg.mediaType == "*/*" ||
+				strings.HasSuffix(rg.mediaType, "/*") && strings.HasPrefix(mt, strings.TrimSuffix(rg.mediaType, "*")) {
+				return mt, true
+			}
+		}
+	}
+	return "", false
+}
+
+func hasEntries(header []string) bool {
+	for _, h := range header {
+		if strings.Trim(h, " ,") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func supported(mediaType string) bool {
+	for _, mt := range mediaTypes {
+		if mt == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// wholeMessage hides XXX_ResponseBody, so the gateway marshals the entire
+// RPC response instead of the field named by response_body.
+type wholeMessage struct{ proto.Message }
+
+// wholeProtobufResponse sends protobuf clients the RPC's response message:
+// a bare repeated field (response_body: "products") is not a message and
+// cannot be encoded on its own.
+func wholeProtobufResponse(ctx context.Context, resp proto.Message) (any, error) {
+	if mt, _ := ctx.Value(mediaTypeKey{}).(string); mt == MIMEProtobuf {
+		return wholeMessage{resp}, nil
+	}
+	return resp, nil
+}
+
+type protobufMarshaler struct {
+	runtime.ProtoMarshaller
+}
+
+func (*protobufMarshaler) ContentType(_ any) string {
+	return MIMEProtobuf
+}
+
+// ndjsonMarshaler writes one JSON document per line: one per element for
+// lists, a single line otherwise. Request bodies are a single document,
+// read as strictly as JSON ones.
+type ndjsonMarshaler struct {
+	*strictJSON
+}
+
+func (*ndjsonMarshaler) ContentType(_ any) string {
+	return MIMENDJSON
+}
+
+func (m *ndjsonMarshaler) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, item := range items(v) {
+		line, err := m.JSONPb.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *ndjsonMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return encoderOf(m, w)
+}
+
+// csvMarshaler writes a header row of proto field names and one row per
+// message. Repeated scalars are joined with csvListSeparator and nested
+// messages are written as JSON. Request bodies are a header row and one
+// data row; unknown columns are rejected.
+type csvMarshaler struct{}
+
+func (*csvMarshaler) ContentType(_ any) string {
+	return MIMECSV
+}
+
+func (*csvMarshaler) Marshal(v any) ([]byte, error) {
+	md := descriptorOf(v)
+	if md == nil {
+		return nil, fmt.Errorf("csv: cannot encode %T", v)
+	}
+	fields := md.Fields()
+	header := make([]string, fields.Len())
+	for i := range header {
+		header[i] = string(fields.Get(i).Name())
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	for _, item := range items(v) {
+		raw, err := jsonMarshaler.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(header))
+		for i, name := range header {
+			row[i] = csvCell(obj[name])
+		}
+		if err := cw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+	return buf.Bytes(), cw.Error()
+}
+
+func csvCell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		cells := make([]string, len(list))
+		for i, item := range list {
+			if len(item) > 0 && (item[0] == '{' || item[0] == '[') {
+				// Lists of messages stay JSON as a whole.
+				return compactJSON(raw)
+			}
+			cells[i] = csvCell(item)
+		}
+		return strings.Join(cells, csvListSeparator)
+	}
+	return compactJSON(raw)
+}
+
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+func (m *csvMarshaler) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("csv: cannot decode into %T", v)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) != 2 {
+		return errors.New("csv: expected a header row and exactly one data row")
+	}
+
+	fields := msg.ProtoReflect().Descriptor().Fields()
+	obj := map[string]json.RawMessage{}
+	for i, name := range records[0] {
+		f := fields.ByName(protoreflect.Name(name))
+		if f == nil {
+			return fmt.Errorf("csv: unknown column %q", name)
+		}
+		if i >= len(records[1]) || records[1][i] == "" {
+			continue
+		}
+		cell := records[1][i]
+		switch {
+		case f.Kind() == protoreflect.MessageKind && f.Message().FullName() != "google.protobuf.Timestamp",
+			f.IsList() && f.Kind() == protoreflect.MessageKind:
+			obj[name] = json.RawMessage(cell)
+		case f.IsList():
+			parts := strings.Split(cell, csvListSeparator)
+			values := make([]json.RawMessage, len(parts))
+			for j, p := range parts {
+				if values[j], err = csvScalar(f, p); err != nil {
+					return err
+				}
+			}
+			if obj[name], err = json.Marshal(values); err != nil {
+				return err
+			}
+		default:
+			if obj[name], err = csvScalar(f, cell); err != nil {
+				return err
+			}
+		}
+	}
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return unmarshalTranscoded(raw, msg)
+}
+
+// csvScalar converts a cell to the JSON protojson expects; protojson takes
+// numbers as strings, but booleans must be literals.
+func csvScalar(f protoreflect.FieldDescriptor, cell string) (json.RawMessage, error) {
+	if f.Kind() == protoreflect.BoolKind {
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return nil, fmt.Errorf("csv: %s: %w", f.Name(), err)
+		}
+		return json.Marshal(b)
+	}
+	return json.Marshal(cell)
+}
+
+func (m *csvMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return decoderOf(m, r)
+}
+
+func (m *csvMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return encoderOf(m, w)
+}
+
+// msgpackMarshaler encodes messages as MessagePack maps keyed by proto field
+// names, with native integers and timestamps.
+type msgpackMarshaler struct{}
+
+func (*msgpackMarshaler) ContentType(_ any) string {
+	return MIMEMsgpack
+}
+
+func (*msgpackMarshaler) Marshal(v any) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return msgpack.Marshal(messageValue(msg.ProtoReflect()))
+	}
+	list := items(v)
+	out := make([]any, len(list))
+	for i, item := range list {
+		out[i] = messageValue(item.ProtoReflect())
+	}
+	return msgpack.Marshal(out)
+}
+
+// Unmarshal goes through JSON so protojson's rules (field names, well-known
+// types, unknown fields) apply to MessagePack bodies too.
+func (*msgpackMarshaler) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("msgpack: cannot decode into %T", v)
+	}
+	var decoded any
+	if err := msgpack.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	return unmarshalTranscoded(raw, msg)
+}
+
+func (m *msgpackMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return decoderOf(m, r)
+}
+
+func (m *msgpackMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return encoderOf(m, w)
+}
+
+func messageValue(m protoreflect.Message) any {
+	if m.Descriptor().FullName() == "google.protobuf.Timestamp" {
+		if ts, ok := m.Interface().(*timestamppb.Timestamp); ok {
+			return ts.AsTime()
+		}
+	}
+	out := map[string]any{}
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		name := string(f.Name())
+		switch {
+		case f.IsList():
+			list := m.Get(f).List()
+			values := make([]any, list.Len())
+			for j := range values {
+				values[j] = scalarValue(f, list.Get(j))
+			}
+			out[name] = values
+		case f.IsMap():
+			entries := map[string]any{}
+			m.Get(f).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				entries[k.String()] = scalarValue(f.MapValue(), v)
+				return true
+			})
+			out[name] = entries
+		case f.HasPresence() && !m.Has(f):
+			// Like protojson: unset messages are null, unset optional
+			// scalars are left out.
+			if f.Kind() == protoreflect.MessageKind {
+				out[name] = nil
+			}
+		default:
+			out[name] = scalarValue(f, m.Get(f))
+		}
+	}
+	return out
+}
+
+func scalarValue(f protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageValue(v.Message())
+	case protoreflect.EnumKind:
+		if ev := f.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
+
+// items lists the messages in v: v itself, or the elements of a slice of
+// messages (what the gateway passes for a repeated response_body).
+func items(v any) []proto.Message {
+	if msg, ok := v.(proto.Message); ok {
+		return []proto.Message{msg}
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	out := make([]proto.Message, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		if msg, ok := rv.Index(i).Interface().(proto.Message); ok {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// descriptorOf describes v, or the element type when v is a slice, so an
+// empty list still gets its CSV header.
+func descriptorOf(v any) protoreflect.MessageDescriptor {
+	if msg, ok := v.(proto.Message); ok {
+		return msg.ProtoReflect().Descriptor()
+	}
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Slice {
+		return nil
+	}
+	if msg, ok := reflect.Zero(t.Elem()).Interface().(proto.Message); ok {
+		return msg.ProtoReflect().Descriptor()
+	}
+	return nil
+}
+
+// decoderOf reads the whole body and unmarshals it as one value. An empty
+// body is io.EOF, which the gateway treats as an empty message.
+func decoderOf(m runtime.Marshaler, r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v any) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			return io.EOF
+		}
+		return m.Unmarshal(data, v)
+	})
+}
+
+func encoderOf(m runtime.Marshaler, w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v any) error {
+		data, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
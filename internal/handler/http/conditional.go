@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// catalogValidator is implemented by the product service handler: it
+// returns the validators of the product listing without reading it.
+type catalogValidator interface {
+	CatalogValidators(ctx context.Context) (etag string, modified time.Time, err error)
+}
+
+// representationTags tell apart the ETags of the encodings a resource is
+// served in, since a strong ETag names one exact representation.
+var representationTags = map[string]string{
+	MIMEJSON:     "json",
+	MIMEProtobuf: "pb",
+	MIMENDJSON:   "ndjson",
+	MIMECSV:      "csv",
+	MIMEMsgpack:  "msgpack",
+}
+
+// conditional answers GETs whose response carries validators (the ETag and
+// Last-Modified the services send as metadata) with 304 Not Modified when
+// If-None-Match or If-Modified-Since says the client is up to date. ETags
+// are qualified with the negotiated encoding. A conditional GET /products
+// is checked against catalog first, so an unchanged catalog is never read.
+// It runs after negotiate, which picks the encoding.
+func conditional(catalog catalogValidator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		mediaType, _ := r.Context().Value(mediaTypeKey{}).(string)
+
+		if catalog != nil && r.URL.Path == "/products" && isConditional(r) {
+			etag, modified, err := catalog.CatalogValidators(r.Context())
+			if err == nil {
+				etag = qualifyETag(etag, mediaType)
+				if notModified(r, etag, modified) {
+					setValidatorHeaders(w.Header(), etag, modified)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+			// On errors the call itself reports the problem.
+		}
+
+		next.ServeHTTP(&validatorWriter{ResponseWriter: w, r: r, mediaType: mediaType}, r)
+	})
+}
+
+// validatorWriter qualifies the ETag of a 200 response and turns it into a
+// 304, dropping the body, when the request's preconditions match.
+type validatorWriter struct {
+	http.ResponseWriter
+	r         *http.Request
+	mediaType string
+
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *validatorWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.wroteHeader = true
+	h := w.Header()
+	if code != http.StatusOK || (h.Get("ETag") == "" && h.Get("Last-Modified") == "") {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	etag := h.Get("ETag")
+	if etag != "" {
+		etag = qualifyETag(etag, w.mediaType)
+		h.Set("ETag", etag)
+	}
+	modified, _ := http.ParseTime(h.Get("Last-Modified"))
+	if !notModified(w.r, etag, modified) {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.discard = true
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(http.StatusNotModified)
+}
+
+func (w *validatorWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *validatorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func isConditional(r *http.Request) bool {
+	return r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != ""
+}
+
+// qualifyETag appends the encoding tag to an ETag: "c12-x" becomes
+// "c12-x-json".
+func qualifyETag(etag, mediaType string) string {
+	tag, ok := representationTags[mediaType]
+	if !ok || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + tag + `"`
+}
+
+func setValidatorHeaders(h http.Header, etag string, modified time.Time) {
+	h.Set("ETag", etag)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is
+// no If-None-Match, as RFC 9110 section 13.2.2 orders them. ETags compare
+// weakly, so a validator weakened by compression still matches.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Values("If-None-Match"); len(inm) > 0 {
+		if etag == "" {
+			return false
+		}
+		for _, header := range inm {
+			for _, candidate := range strings.Split(header, ",") {
+				candidate = strings.TrimSpace(candidate)
+				if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
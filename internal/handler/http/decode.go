@@ -0,0 +1,148 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"simple-crud/internal/problem"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+// strictJSON is the JSON marshaler of the gateway. Request bodies must be
+// exactly one JSON object with no unknown fields; errors name the line and
+// column where decoding failed.
+type strictJSON struct {
+	*runtime.JSONPb
+}
+
+func (m *strictJSON) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot decode a request body into %T", v)
+	}
+	// protojson, unlike runtime.JSONPb, rejects anything after the value.
+	if err := m.UnmarshalOptions.Unmarshal(data, msg); err != nil {
+		return bodyError(err, true)
+	}
+	return nil
+}
+
+func (m *strictJSON) NewDecoder(r io.Reader) runtime.Decoder {
+	return decoderOf(m, r)
+}
+
+// unmarshalTranscoded decodes JSON the codec built from another encoding
+// (CSV, MessagePack), with the same rules as strictJSON. Locations would
+// point into the intermediate JSON, so errors leave them out.
+func unmarshalTranscoded(data []byte, msg proto.Message) error {
+	if err := jsonMarshaler.UnmarshalOptions.Unmarshal(data, msg); err != nil {
+		return bodyError(err, false)
+	}
+	return nil
+}
+
+// protojsonLocation matches the position protojson puts in its errors,
+// e.g. `proto: syntax error (line 1:14): invalid value x`.
+var protojsonLocation = regexp.MustCompile(`\(line (\d+):(\d+)\): (.*)$`)
+
+// bodyError rewrites a protojson error as "line L, column C: <problem>".
+func bodyError(err error, withLocation bool) error {
+	msg := err.Error()
+	m := protojsonLocation.FindStringSubmatch(msg)
+	switch {
+	case m == nil && strings.HasSuffix(msg, "unexpected EOF"):
+		return errors.New("request body: unexpected end of JSON input")
+	case m == nil:
+		return fmt.Errorf("request body: %w", err)
+	}
+	detail := m[3]
+	if strings.Contains(msg, "syntax error") {
+		detail = "syntax error: " + strings.TrimSpace(detail)
+	}
+	if !withLocation {
+		return errors.New("request body: " + detail)
+	}
+	return fmt.Errorf("request body: line %s, column %s: %s", m[1], m[2], detail)
+}
+
+type bodyLimitKey struct{}
+
+// bodyLimit records whether a request body was cut off at max bytes.
+type bodyLimit struct {
+	max      int64
+	exceeded bool
+}
+
+// limitBody caps request bodies at maxBytes (413 when exceeded), so the
+// gateway never buffers more than that. Bodies declaring a larger
+// Content-Length are refused before reading; others are cut off while
+// decoding, and gatewayError turns the failure into the 413.
+func limitBody(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			bodyTooLarge(w, r, maxBytes)
+			return
+		}
+		lim := &bodyLimit{max: maxBytes}
+		r = r.WithContext(context.WithValue(r.Context(), bodyLimitKey{}, lim))
+		r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes), limit: lim}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limit *bodyLimit
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.limit.exceeded = true
+	}
+	return n, err
+}
+
+// exceededBodyLimit returns the body limit of r if its body was cut off.
+func exceededBodyLimit(r *http.Request) (int64, bool) {
+	lim, ok := r.Context().Value(bodyLimitKey{}).(*bodyLimit)
+	if !ok || !lim.exceeded {
+		return 0, false
+	}
+	return lim.max, true
+}
+
+func bodyTooLarge(w http.ResponseWriter, r *http.Request, maxBytes int64) {
+	problem.Error(w, r, "request body exceeds "+strconv.FormatInt(maxBytes, 10)+" bytes", http.StatusRequestEntityTooLarge)
+}
+
+// checkContentType requires a request with a body to declare a supported
+// media type, in UTF-8 if it names a charset. It returns the problem
+// detail, or "" when the request is acceptable.
+func checkContentType(r *http.Request) string {
+	if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return "Content-Type is required with a request body; use one of " + strings.Join(mediaTypes, ", ")
+	}
+	mt, params, err := mime.ParseMediaType(ct)
+	if err != nil || !supported(mt) {
+		return "unsupported Content-Type " + strconv.Quote(ct) + "; use one of " + strings.Join(mediaTypes, ", ")
+	}
+	if cs, ok := params["charset"]; ok && !strings.EqualFold(cs, "utf-8") {
+		return "unsupported charset " + strconv.Quote(cs) + "; request bodies must be UTF-8"
+	}
+	return ""
+}
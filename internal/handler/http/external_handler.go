@@ -9,6 +9,7 @@ import (
 	"simple-crud/internal/client"
 	"simple-crud/internal/config"
 	"simple-crud/internal/logger"
+	"simple-crud/internal/problem"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -39,11 +40,15 @@ func (h *ExternalHandler) Fetch(w http.ResponseWriter, r *http.Request) {
 	// Call external HTTP
 	cfg := config.Instance()
 	httpClient := client.NewHTTPClient(cfg.ExternalHTTP, 3*time.Second)
+	if cfg.ClientAPIKey != "" {
+		httpClient.SetDefaultHeader("X-API-Key", cfg.ClientAPIKey)
+	}
 
 	resp, err := httpClient.GetWithResponse("/products", client.RequestOptions{
 		Context: ctx,
 	})
 	if err != nil {
+		problem.Error(w, r, "Failed to reach the external catalog", http.StatusBadGateway)
 		return
 	}
 
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/problem"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GatewayServices are the gRPC implementations the HTTP API transcodes to.
+type GatewayServices struct {
+	Product pb.ProductServiceServer
+	Report  pb.ReportServiceServer
+	Webhook pb.WebhookServiceServer
+	Price   pb.PriceServiceServer
+	APIKey  pb.ApiKeyServiceServer
+}
+
+// createdMethods answer 201 instead of 200 over HTTP.
+var createdMethods = map[string]bool{
+	pb.WebhookService_CreateSubscription_FullMethodName: true,
+	pb.PriceService_CreateSchedule_FullMethodName:       true,
+	pb.ApiKeyService_CreateKey_FullMethodName:           true,
+}
+
+// NewGateway transcodes HTTP/JSON to the gRPC services using the
+// google.api.http bindings in product.proto. Calls go straight to the
+// service implementations in-process, so the HTTP middleware (tracing,
+// Mongo sessions) stays in charge and no gRPC interceptors run twice.
+// Responses are encoded as negotiated from the Accept header; see codec.go.
+// Request bodies are limited to maxBodyBytes and decoded strictly; see
+// decode.go. GETs are answered conditionally; see conditional.go.
+func NewGateway(ctx context.Context, svc GatewayServices, maxBodyBytes int64) (http.Handler, error) {
+	opts := append(marshalerOptions(),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+		runtime.WithForwardResponseOption(setCreatedStatus),
+		runtime.SetQueryParameterParser(commaListQueryParser{}),
+		runtime.WithErrorHandler(gatewayError),
+		runtime.WithRoutingErrorHandler(routingError),
+	)
+	mux := runtime.NewServeMux(opts...)
+	if err := pb.RegisterProductServiceHandlerServer(ctx, mux, svc.Product); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterReportServiceHandlerServer(ctx, mux, svc.Report); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterWebhookServiceHandlerServer(ctx, mux, svc.Webhook); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterPriceServiceHandlerServer(ctx, mux, svc.Price); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterApiKeyServiceHandlerServer(ctx, mux, svc.APIKey); err != nil {
+		return nil, err
+	}
+	catalog, _ := svc.Product.(catalogValidator)
+	return propagated(negotiate(conditional(catalog, limitBody(maxBodyBytes, mux)))), nil
+}
+
+// outgoingHeader passes plain HTTP headers set by the services through as
+// is; other metadata keeps the gateway's Grpc-Metadata- prefix.
+func outgoingHeader(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case "cache-control":
+		return "Cache-Control", true
+	case "etag":
+		return "ETag", true
+	case "last-modified":
+		return "Last-Modified", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+func setCreatedStatus(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	if method, ok := runtime.RPCMethod(ctx); ok && createdMethods[method] {
+		w.WriteHeader(http.StatusCreated)
+	}
+	return nil
+}
+
+// commaListQueryParser also accepts repeated scalar fields as one
+// comma-separated value (?boundaries=0,100,500), the form the HTTP API took
+// before transcoding.
+type commaListQueryParser struct{}
+
+func (commaListQueryParser) Parse(msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	fields := msg.ProtoReflect().Descriptor().Fields()
+	for key, vs := range values {
+		f := fields.ByName(protoreflect.Name(key))
+		if f == nil || !f.IsList() || f.Kind() == protoreflect.StringKind || len(vs) != 1 {
+			continue
+		}
+		if parts := strings.Split(vs[0], ","); len(parts) > 1 {
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+			values[key] = parts
+		}
+	}
+	return (&runtime.DefaultQueryParser{}).Parse(msg, values, filter)
+}
+
+// fromQuery serves a deprecated query-string route by moving the query
+// parameters named in the successor's path template into the path, e.g.
+// /product?id=X becomes /products/X.
+func fromQuery(successor string, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		segments := strings.Split(successor, "/")
+		for i, seg := range segments {
+			if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+				continue
+			}
+			name := seg[1 : len(seg)-1]
+			value := query.Get(name)
+			if value == "" {
+				problem.Error(w, r, name+" is required", http.StatusBadRequest)
+				return
+			}
+			segments[i] = url.PathEscape(value)
+			query.Del(name)
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = strings.Join(segments, "/")
+		r2.URL.RawPath = ""
+		r2.URL.RawQuery = query.Encode()
+		next.ServeHTTP(w, r2)
+	}
+}
+
+// gatewayError writes a failed call as a problem: the HTTP status follows
+// the gRPC code, and BadRequest field violations become the "errors"
+// member. Internal errors keep their detail out of the response.
+func gatewayError(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	// The gateway reports a body cut off by limitBody as a plain decoding
+	// failure.
+	if maxBytes, ok := exceededBodyLimit(r); ok {
+		bodyTooLarge(w, r, maxBytes)
+		return
+	}
+	st := status.Convert(err)
+	code := runtime.HTTPStatusFromCode(st.Code())
+
+	var fields []problem.FieldError
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, problem.FieldError{Field: v.GetField(), Message: v.GetDescription()})
+			}
+		}
+	}
+
+	var p *problem.Problem
+	switch {
+	case len(fields) > 0:
+		p = problem.Validation(st.Message(), fields)
+	case code == http.StatusInternalServerError:
+		logger.Error(ctx, "Gateway call failed",
+			slog.String("exception.message", st.Message()),
+			slog.String("exception.type", st.Code().String()),
+		)
+		p = problem.New(code, "")
+		p.Type = problem.TypeInternal
+	default:
+		p = problem.New(code, st.Message())
+	}
+	problem.Write(w, r, p)
+}
+
+// routingError answers requests the gateway has no binding for.
+func routingError(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, code int) {
+	problem.Error(w, r, "", code)
+}
+
+// propagated puts the trace context the tracing middleware forwarded in the
+// request headers into the request context, so the gRPC handler spans join
+// the HTTP request's trace.
+func propagated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
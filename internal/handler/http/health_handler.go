@@ -1,9 +1,11 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
+	"simple-crud/internal/health"
 	"simple-crud/internal/logger"
 	"simple-crud/internal/service"
 
@@ -23,32 +25,70 @@ func NewHealthHandler(service *service.HealthService) *HealthHandler {
 	}
 }
 
-func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
-	parentCtx := r.Context()
+// Live serves /livez.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	h.probe(w, r, "HttpHealthHandler.Live", h.service.Live)
+}
+
+// Ready serves /readyz.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	h.probe(w, r, "HttpHealthHandler.Ready", h.service.Ready)
+}
+
+// Startup serves /startupz.
+func (h *HealthHandler) Startup(w http.ResponseWriter, r *http.Request) {
+	h.probe(w, r, "HttpHealthHandler.Startup", h.service.Startup)
+}
+
+// probe answers 200 when the probe passes (degraded included) and 503 when
+// it fails. The per-check report is only sent with ?verbose.
+func (h *HealthHandler) probe(w http.ResponseWriter, r *http.Request, name string, run func(context.Context) health.Report) {
+	// Extract context from incoming headers (traceparent, etc.)
+	propCtx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := HttpHealthHandlerTracer.Start(propCtx, name)
+	defer span.End()
+	logger.Info(ctx, name)
 
-	// Start span with extracted context
+	rep := run(ctx)
+	if !r.URL.Query().Has("verbose") {
+		rep.Checks = nil
+	}
+
+	status := http.StatusOK
+	if !rep.Up() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(rep)
+}
+
+// Check serves /healthz, the readiness report in its original shape:
+// every check's status under data.
+func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	// Extract context from incoming headers (traceparent, etc.)
-	propCtx := otel.GetTextMapPropagator().Extract(parentCtx, propagation.HeaderCarrier(r.Header))
+	propCtx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 	ctx, span := HttpHealthHandlerTracer.Start(propCtx, "HttpHealthHandler.Check")
 	defer span.End()
 	logger.Info(ctx, "HttpHealthHandler.Check")
 
-	status := h.service.Check(propCtx)
+	rep := h.service.Ready(ctx)
 
-	overall := "UP"
-	if status.Mongo == "DOWN" {
-		overall = "DOWN"
-		w.WriteHeader(http.StatusInternalServerError)
+	overall, status := "UP", http.StatusOK
+	if !rep.Up() {
+		overall, status = "DOWN", http.StatusInternalServerError
+	}
+	data := make(map[string]string, len(rep.Checks))
+	for _, c := range rep.Checks {
+		data[c.Name] = c.Status
 	}
-
 	resp := map[string]interface{}{
 		"status": overall,
-		"data": map[string]string{
-			"mongodb": status.Mongo,
-		},
+		"data":   data,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,637 @@
+package http
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/model"
+	"simple-crud/internal/openapi"
+	"simple-crud/internal/problem"
+	"simple-crud/internal/version"
+)
+
+//go:embed static/docs.html
+var docsPage []byte
+
+// DocsHandler serves the OpenAPI document and a Redoc page rendering it.
+type DocsHandler struct {
+	spec []byte
+}
+
+func NewDocsHandler(doc *openapi.Document) (*DocsHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &DocsHandler{spec: spec}, nil
+}
+
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(h.spec)
+}
+
+func (h *DocsHandler) UI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(docsPage)
+}
+
+// CheckSpec fails when the registered routes and the OpenAPI document
+// disagree, so a route cannot be added or removed without its docs.
+func CheckSpec(doc *openapi.Document, routes []Route) error {
+	patterns := make([]string, 0, len(routes))
+	for _, rt := range routes {
+		patterns = append(patterns, rt.Pattern())
+	}
+	return doc.CheckRoutes(patterns)
+}
+
+// OpenAPI describes every route in Routes. Keep the two in step; CheckSpec
+// enforces it at startup, as does TestOpenAPIMatchesRoutes.
+func OpenAPI() *openapi.Document {
+	d := openapi.New(openapi.Info{
+		Title:   "simple-crud",
+		Version: version.Version,
+		Description: "Product catalog API. The query-string routes (`/product?id=`, ...) are " +
+			"deprecated aliases of the resource routes and answer with a `Deprecation` header. " +
+			"Routes whose bodies are protobuf messages also read and write `application/x-protobuf` " +
+			"(the full RPC message), `application/x-ndjson`, `text/csv` and `application/msgpack`, " +
+			"chosen by `Accept` and `Content-Type`; unsupported types get 406 and 415. " +
+			"Request bodies need a `Content-Type`, must hold a single value without unknown " +
+			"fields (400 naming the line and column otherwise) and are capped at " +
+			"`HTTP_MAX_BODY_BYTES` (413). " +
+			"Responses are compressed (zstd, br or gzip) as negotiated by `Accept-Encoding`. " +
+			"Product reads send `ETag` and `Last-Modified` and answer conditional requests with 304. " +
+			"Errors are `application/problem+json` (RFC 9457) with the request's `trace_id`. " +
+			"With authentication enabled, every route outside `meta` needs a bearer JWT or an " +
+			"`X-API-Key` granting its scope (`products:read`, `products:write` or `admin`) " +
+			"and answers 401 or 403 otherwise. A role policy (`POLICY_FILE`) may further limit " +
+			"product actions by role, field and category; its denials are 403 as well. " +
+			"With rate limiting enabled, limited routes send `RateLimit-*` headers and answer " +
+			"429 with `Retry-After` once the client's bucket is empty. Under overload, requests " +
+			"may be shed with a 503 and `Retry-After`, bulk reads first.",
+	})
+
+	// Errors are RFC 9457 problems; see problemResponse.
+	d.SchemaOf(problem.Problem{})
+
+	// Routes served by the gateway encode protobuf messages with protojson.
+	product := d.SchemaOfMessage(&pb.Product{})
+	products := d.ArrayOfMessage(&pb.Product{})
+	attachments := d.ArrayOf(model.Attachment{})
+	schedule := d.SchemaOfMessage(&pb.PriceSchedule{})
+	webhook := d.SchemaOfMessage(&pb.WebhookSubscription{})
+	delivery := d.SchemaOfMessage(&pb.WebhookDelivery{})
+	empty := &openapi.Schema{Type: "object"}
+
+	productID := pathParamSpec("id", "Product ID")
+
+	d.Add(http.MethodGet, "/{$}", &openapi.Operation{
+		OperationID: "root",
+		Summary:     "Hello world",
+		Tags:        []string{"meta"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("Greeting", &openapi.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi.Schema{"data": {Type: "string"}},
+			}),
+		},
+	})
+	d.Add(http.MethodGet, "/openapi.json", &openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "This document",
+		Tags:        []string{"meta"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("OpenAPI 3.1 document", &openapi.Schema{Type: "object"}),
+		},
+	})
+	d.Add(http.MethodGet, "/docs", &openapi.Operation{
+		OperationID: "getDocs",
+		Summary:     "API reference page",
+		Tags:        []string{"meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "HTML page", Content: map[string]openapi.MediaType{"text/html": {Schema: &openapi.Schema{Type: "string"}}}},
+		},
+	})
+
+	// Products
+	getAll := &openapi.Operation{
+		OperationID: "listProducts",
+		Summary:     "List all products",
+		Tags:        []string{"products"},
+		Description: "Conditional: the ETag comes from the catalog's change marker, so an unchanged catalog is answered with 304 without being read.",
+		Parameters:  conditionalParams,
+		Responses:   map[string]*openapi.Response{"200": cached(jsonResponse("Products", products)), "304": notModifiedResponse, "500": problemResponse("Failed to fetch products")},
+	}
+	create := &openapi.Operation{
+		OperationID: "createProduct",
+		Summary:     "Create a product",
+		Tags:        []string{"products"},
+		RequestBody: jsonBody(product),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("Created product", product), "400": problemResponse("Invalid product")},
+	}
+	get := &openapi.Operation{
+		OperationID: "getProduct",
+		Summary:     "Get a product",
+		Description: "With `as_of`, the price is the one in effect at that time. Conditional: the ETag hashes the product.",
+		Tags:        []string{"products"},
+		Parameters:  append([]openapi.Parameter{productID, queryParamSpec("as_of", "RFC 3339 timestamp", dateTime)}, conditionalParams...),
+		Responses:   map[string]*openapi.Response{"200": cached(jsonResponse("Product", product)), "304": notModifiedResponse, "400": problemResponse("Invalid request"), "404": problemResponse("Product not found")},
+	}
+	update := &openapi.Operation{
+		OperationID: "updateProduct",
+		Summary:     "Replace a product's fields",
+		Tags:        []string{"products"},
+		Parameters:  []openapi.Parameter{productID},
+		RequestBody: jsonBody(product),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("Updated product", product), "400": problemResponse("Invalid request"), "404": problemResponse("Product not found"), "500": problemResponse("Update failed")},
+	}
+	del := &openapi.Operation{
+		OperationID: "deleteProduct",
+		Summary:     "Delete a product and its attachments",
+		Tags:        []string{"products"},
+		Parameters:  []openapi.Parameter{productID},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("Deleted", empty), "400": problemResponse("Invalid request"), "404": problemResponse("Product not found"), "500": problemResponse("Delete failed")},
+	}
+	d.Add(http.MethodGet, "/products", getAll)
+	d.Add(http.MethodPost, "/products", create)
+	d.Add(http.MethodGet, "/products/{id}", get)
+	d.Add(http.MethodPut, "/products/{id}", update)
+	d.Add(http.MethodDelete, "/products/{id}", del)
+
+	// Attachments
+	fileID := pathParamSpec("file", "Attachment ID")
+	attachmentErrors := map[string]*openapi.Response{
+		"400": problemResponse("Invalid request"),
+		"404": problemResponse("Product or attachment not found"),
+	}
+	listImages := &openapi.Operation{
+		OperationID: "listProductImages",
+		Summary:     "List a product's attachments",
+		Tags:        []string{"attachments"},
+		Parameters:  []openapi.Parameter{productID},
+		Responses:   with(attachmentErrors, "200", jsonResponse("Attachments", attachments)),
+	}
+	upload := &openapi.Operation{
+		OperationID: "uploadProductImages",
+		Summary:     "Upload attachments",
+		Description: "Every file part of the multipart body is stored as one attachment.",
+		Tags:        []string{"attachments"},
+		Parameters:  []openapi.Parameter{productID},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			"multipart/form-data": {Schema: &openapi.Schema{
+				Type: "object",
+				Properties: map[string]*openapi.Schema{
+					"file": {Type: "array", Items: &openapi.Schema{Type: "string", ContentMediaType: "application/octet-stream"}},
+				},
+			}},
+		}},
+		Responses: with(attachmentErrors,
+			"201", jsonResponse("Stored attachments", attachments),
+			"413", problemResponse("File too large"),
+			"415", problemResponse("File type not allowed"),
+		),
+	}
+	download := &openapi.Operation{
+		OperationID: "downloadProductImage",
+		Summary:     "Download an attachment",
+		Description: "Supports range and conditional requests.",
+		Tags:        []string{"attachments"},
+		Parameters:  []openapi.Parameter{productID, fileID},
+		Responses: with(attachmentErrors,
+			"200", binaryResponse("File content"),
+			"206", binaryResponse("Requested range"),
+		),
+	}
+	deleteImage := &openapi.Operation{
+		OperationID: "deleteProductImage",
+		Summary:     "Delete an attachment",
+		Tags:        []string{"attachments"},
+		Parameters:  []openapi.Parameter{productID, fileID},
+		Responses:   with(attachmentErrors, "204", &openapi.Response{Description: "Deleted"}),
+	}
+	d.Add(http.MethodGet, "/products/{id}/images", listImages)
+	d.Add(http.MethodPost, "/products/{id}/images", upload)
+	d.Add(http.MethodGet, "/products/{id}/images/{file}", download)
+	d.Add(http.MethodDelete, "/products/{id}/images/{file}", deleteImage)
+
+	// Prices
+	priceErrors := map[string]*openapi.Response{
+		"400": problemResponse("Invalid request"),
+		"404": problemResponse("Product or schedule not found"),
+	}
+	listSchedules := &openapi.Operation{
+		OperationID: "listPriceSchedules",
+		Summary:     "List a product's price schedules",
+		Tags:        []string{"prices"},
+		Parameters:  []openapi.Parameter{productID},
+		Responses:   with(priceErrors, "200", jsonResponse("Schedules", d.ArrayOfMessage(&pb.PriceSchedule{}))),
+	}
+	createSchedule := &openapi.Operation{
+		OperationID: "createPriceSchedule",
+		Summary:     "Schedule a price change",
+		Description: "With `ends_at`, the previous price is restored when the schedule ends.",
+		Tags:        []string{"prices"},
+		Parameters:  []openapi.Parameter{productID},
+		RequestBody: jsonBody(schedule),
+		Responses:   with(priceErrors, "201", jsonResponse("Created schedule", schedule), "400", problemResponse("Invalid schedule, or it overlaps another schedule")),
+	}
+	history := &openapi.Operation{
+		OperationID: "getPriceHistory",
+		Summary:     "Price history of a product",
+		Tags:        []string{"prices"},
+		Parameters: []openapi.Parameter{
+			productID,
+			queryParamSpec("from", "RFC 3339 timestamp", dateTime),
+			queryParamSpec("to", "RFC 3339 timestamp", dateTime),
+		},
+		Responses: with(priceErrors, "200", jsonResponse("Price changes", d.ArrayOfMessage(&pb.PriceChange{}))),
+	}
+	cancelSchedule := &openapi.Operation{
+		OperationID: "cancelPriceSchedule",
+		Summary:     "Cancel a price schedule",
+		Description: "A schedule that is already active ends now and restores the previous price.",
+		Tags:        []string{"prices"},
+		Parameters:  []openapi.Parameter{pathParamSpec("schedule", "Schedule ID")},
+		Responses:   with(priceErrors, "200", jsonResponse("Cancelled schedule", schedule), "400", problemResponse("Invalid request, or the schedule already finished")),
+	}
+	d.Add(http.MethodGet, "/products/{id}/prices/schedules", listSchedules)
+	d.Add(http.MethodPost, "/products/{id}/prices/schedules", createSchedule)
+	d.Add(http.MethodGet, "/products/{id}/prices/history", history)
+	d.Add(http.MethodDelete, "/prices/schedules/{schedule}", cancelSchedule)
+
+	// Reports
+	reportErrors := map[string]*openapi.Response{
+		"400": problemResponse("Invalid parameters"),
+		"504": problemResponse("Report timed out"),
+	}
+	d.Add(http.MethodGet, "/reports/inventory-value", &openapi.Operation{
+		OperationID: "reportInventoryValue",
+		Summary:     "Total stock and value",
+		Tags:        []string{"reports"},
+		Responses:   with(reportErrors, "200", jsonResponse("Inventory value", d.SchemaOfMessage(&pb.InventoryValueRes{}))),
+	})
+	d.Add(http.MethodGet, "/reports/low-stock", &openapi.Operation{
+		OperationID: "reportLowStock",
+		Summary:     "Products at or below a stock threshold",
+		Tags:        []string{"reports"},
+		Parameters: []openapi.Parameter{
+			queryParamSpec("threshold", "Stock threshold, 10 when omitted", &openapi.Schema{Type: "integer"}),
+			queryParamSpec("limit", "Maximum number of products", &openapi.Schema{Type: "integer"}),
+		},
+		Responses: with(reportErrors, "200", jsonResponse("Products", products)),
+	})
+	d.Add(http.MethodGet, "/reports/price-histogram", &openapi.Operation{
+		OperationID: "reportPriceHistogram",
+		Summary:     "Product count per price bucket",
+		Tags:        []string{"reports"},
+		Parameters: []openapi.Parameter{
+			queryParamSpec("boundaries", "Bucket boundaries, repeated or comma-separated", &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "number"}}),
+			queryParamSpec("buckets", "Number of automatic buckets when no boundaries are given", &openapi.Schema{Type: "integer"}),
+		},
+		Responses: with(reportErrors, "200", jsonResponse("Buckets", d.ArrayOfMessage(&pb.PriceBucket{}))),
+	})
+	d.Add(http.MethodGet, "/reports/counts", &openapi.Operation{
+		OperationID: "reportCounts",
+		Summary:     "Product count, stock and value per group",
+		Tags:        []string{"reports"},
+		Parameters: []openapi.Parameter{
+			queryParamSpec("by", "Grouping field", &openapi.Schema{Type: "string", Enum: []string{"category", "tag"}}),
+		},
+		Responses: with(reportErrors, "200", jsonResponse("Groups", d.ArrayOfMessage(&pb.GroupCount{}))),
+	})
+
+	// Webhooks
+	webhookID := pathParamSpec("id", "Subscription ID")
+	webhookErrors := map[string]*openapi.Response{
+		"400": problemResponse("Invalid request"),
+		"404": problemResponse("Subscription or delivery not found"),
+	}
+	getWebhook := &openapi.Operation{
+		OperationID: "getWebhook",
+		Summary:     "Get a webhook subscription",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{webhookID},
+		Responses:   with(webhookErrors, "200", jsonResponse("Subscription", webhook)),
+	}
+	updateWebhook := &openapi.Operation{
+		OperationID: "updateWebhook",
+		Summary:     "Update a webhook subscription",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{webhookID},
+		RequestBody: jsonBody(webhook),
+		Responses:   with(webhookErrors, "200", jsonResponse("Subscription", webhook)),
+	}
+	deleteWebhook := &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook subscription",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{webhookID},
+		Responses:   with(webhookErrors, "200", jsonResponse("Deleted", empty)),
+	}
+	deliveries := &openapi.Operation{
+		OperationID: "listWebhookDeliveries",
+		Summary:     "List a subscription's deliveries",
+		Tags:        []string{"webhooks"},
+		Parameters: []openapi.Parameter{
+			webhookID,
+			queryParamSpec("status", "Delivery status", &openapi.Schema{
+				Type: "string",
+				Enum: []string{model.DeliveryPending, model.DeliveryDelivered, model.DeliveryDead},
+			}),
+			queryParamSpec("limit", "Maximum number of deliveries", &openapi.Schema{Type: "integer"}),
+		},
+		Responses: with(webhookErrors, "200", jsonResponse("Deliveries", d.ArrayOfMessage(&pb.WebhookDelivery{}))),
+	}
+	retry := &openapi.Operation{
+		OperationID: "retryWebhookDelivery",
+		Summary:     "Retry a delivery",
+		Tags:        []string{"webhooks"},
+		Parameters:  []openapi.Parameter{pathParamSpec("id", "Delivery ID")},
+		Responses:   with(webhookErrors, "200", jsonResponse("Rescheduled delivery", delivery)),
+	}
+	d.Add(http.MethodGet, "/webhooks", &openapi.Operation{
+		OperationID: "listWebhooks",
+		Summary:     "List webhook subscriptions",
+		Tags:        []string{"webhooks"},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("Subscriptions", d.ArrayOfMessage(&pb.WebhookSubscription{}))},
+	})
+	d.Add(http.MethodPost, "/webhooks", &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Subscribe to product events",
+		Description: "The signing secret is only returned here.",
+		Tags:        []string{"webhooks"},
+		RequestBody: jsonBody(webhook),
+		Responses:   with(webhookErrors, "201", jsonResponse("Subscription", webhook)),
+	})
+	d.Add(http.MethodGet, "/webhooks/{id}", getWebhook)
+	d.Add(http.MethodPut, "/webhooks/{id}", updateWebhook)
+	d.Add(http.MethodDelete, "/webhooks/{id}", deleteWebhook)
+	d.Add(http.MethodGet, "/webhooks/{id}/deliveries", deliveries)
+	d.Add(http.MethodPost, "/webhooks/deliveries/{id}/retry", retry)
+
+	// API keys
+	apiKey := d.SchemaOfMessage(&pb.ApiKey{})
+	apiKeyID := pathParamSpec("id", "API key ID")
+	apiKeyErrors := map[string]*openapi.Response{
+		"400": problemResponse("Invalid request"),
+		"404": problemResponse("API key not found"),
+	}
+	d.Add(http.MethodGet, "/admin/api-keys", &openapi.Operation{
+		OperationID: "listAPIKeys",
+		Summary:     "List API keys",
+		Tags:        []string{"admin"},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("API keys", d.ArrayOfMessage(&pb.ApiKey{}))},
+	})
+	d.Add(http.MethodPost, "/admin/api-keys", &openapi.Operation{
+		OperationID: "createAPIKey",
+		Summary:     "Issue an API key",
+		Description: "The key is only returned here and by rotate; only its hash is stored.",
+		Tags:        []string{"admin"},
+		RequestBody: jsonBody(apiKey),
+		Responses:   with(apiKeyErrors, "201", jsonResponse("API key", apiKey)),
+	})
+	d.Add(http.MethodGet, "/admin/api-keys/{id}", &openapi.Operation{
+		OperationID: "getAPIKey",
+		Summary:     "Get an API key",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{apiKeyID},
+		Responses:   with(apiKeyErrors, "200", jsonResponse("API key", apiKey)),
+	})
+	d.Add(http.MethodPost, "/admin/api-keys/{id}/rotate", &openapi.Operation{
+		OperationID: "rotateAPIKey",
+		Summary:     "Issue a new secret for an API key",
+		Description: "The replaced key keeps working for `grace_period_sec`.",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{apiKeyID},
+		RequestBody: jsonBody(d.SchemaOfMessage(&pb.RotateApiKeyReq{})),
+		Responses:   with(apiKeyErrors, "200", jsonResponse("API key", apiKey), "400", problemResponse("Invalid request or key revoked")),
+	})
+	d.Add(http.MethodDelete, "/admin/api-keys/{id}", &openapi.Operation{
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke an API key",
+		Description: "Revoked keys are kept, with `revoked_at` set.",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{apiKeyID},
+		Responses:   with(apiKeyErrors, "200", jsonResponse("Revoked API key", apiKey)),
+	})
+
+	// External and health
+	d.Add(http.MethodGet, "/external", &openapi.Operation{
+		OperationID: "listExternalProducts",
+		Summary:     "Products fetched from the configured upstream",
+		Tags:        []string{"products"},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("Products", products), "502": problemResponse("Upstream unreachable")},
+	})
+	statusEnum := []string{"UP", "DEGRADED", "DOWN"}
+	health := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status": {Type: "string", Enum: []string{"UP", "DOWN"}},
+			"data": {
+				Type:                 "object",
+				Description:          "Status of each readiness check by name",
+				AdditionalProperties: &openapi.Schema{Type: "string", Enum: []string{"UP", "DOWN"}},
+			},
+		},
+		Required: []string{"status", "data"},
+	}
+	d.Add(http.MethodGet, "/healthz", &openapi.Operation{
+		OperationID: "health",
+		Summary:     "Dependency health",
+		Description: "The readiness report in its original shape; prefer `/readyz`.",
+		Tags:        []string{"meta"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("Healthy", health),
+			"500": jsonResponse("A critical dependency is down, or the server is starting or draining", health),
+		},
+	})
+	probeReport := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"probe":  {Type: "string", Enum: []string{"liveness", "readiness", "startup"}},
+			"status": {Type: "string", Enum: statusEnum, Description: "`DEGRADED` when only non-critical checks fail"},
+			"checks": {
+				Type:        "array",
+				Description: "Sent with `verbose`",
+				Items: &openapi.Schema{
+					Type: "object",
+					Properties: map[string]*openapi.Schema{
+						"name":        {Type: "string"},
+						"status":      {Type: "string", Enum: []string{"UP", "DOWN"}},
+						"critical":    {Type: "boolean"},
+						"duration_ms": {Type: "integer"},
+						"cached":      {Type: "boolean"},
+						"error":       {Type: "string"},
+					},
+					Required: []string{"name", "status", "critical", "duration_ms"},
+				},
+			},
+		},
+		Required: []string{"probe", "status"},
+	}
+	verbose := queryParamSpec("verbose", "Include the per-check report", &openapi.Schema{Type: "boolean"})
+	probe := func(id, summary, description string) *openapi.Operation {
+		return &openapi.Operation{
+			OperationID: id,
+			Summary:     summary,
+			Description: description,
+			Tags:        []string{"meta"},
+			Parameters:  []openapi.Parameter{verbose},
+			Responses: map[string]*openapi.Response{
+				"200": jsonResponse("Probe passes", probeReport),
+				"503": jsonResponse("Probe fails", probeReport),
+			},
+		}
+	}
+	d.Add(http.MethodGet, "/livez", probe("liveness", "Liveness probe",
+		"Fails only when the process should be restarted; dependency outages never fail it."))
+	d.Add(http.MethodGet, "/readyz", probe("readiness", "Readiness probe",
+		"Fails while starting or draining for shutdown, or when a critical check (MongoDB) fails. "+
+			"Failing non-critical checks (OTLP collector, remote log endpoint, `EXTERNAL_HTTP`) only degrade it."))
+	d.Add(http.MethodGet, "/startupz", probe("startup", "Startup probe",
+		"Passes once the server has started and its critical checks have passed, and from then on."))
+	d.Add(http.MethodGet, "/metrics", &openapi.Operation{
+		OperationID: "metrics",
+		Summary:     "Prometheus metrics",
+		Description: "Request rate, errors and latency per route and gRPC method, MongoDB command latencies, " +
+			"the remote log backlog, Go runtime metrics and build info, in the Prometheus text format.",
+		Tags: []string{"meta"},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "Metrics", Content: map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}}},
+		},
+	})
+
+	// Deprecated aliases take their IDs from the query string.
+	d.Add(http.MethodGet, "/product", legacy(get))
+	d.Add(http.MethodPost, "/product", legacy(create))
+	d.Add(http.MethodPut, "/product", legacy(update))
+	d.Add(http.MethodDelete, "/product", legacy(del))
+	legacyDownload := legacy(download)
+	legacyDownload.Description = "Without `file`, lists the product's attachments."
+	legacyDownload.Parameters[1].Required = false
+	legacyDownload.Responses = with(legacyDownload.Responses, "200", withDeprecationHeaders(&openapi.Response{
+		Description: "File content, or the attachment list when `file` is omitted",
+		Content: map[string]openapi.MediaType{
+			"application/json": {Schema: attachments},
+			"*/*":              {Schema: &openapi.Schema{Type: "string", ContentMediaType: "application/octet-stream"}},
+		},
+	}))
+	d.Add(http.MethodGet, "/product/images", legacyDownload)
+	d.Add(http.MethodPost, "/product/images", legacy(upload))
+	d.Add(http.MethodDelete, "/product/images", legacy(deleteImage))
+	d.Add(http.MethodGet, "/product/prices/schedules", legacy(listSchedules))
+	d.Add(http.MethodPost, "/product/prices/schedules", legacy(createSchedule))
+	d.Add(http.MethodDelete, "/product/prices/schedules", legacy(cancelSchedule))
+	d.Add(http.MethodGet, "/product/prices/history", legacy(history))
+	d.Add(http.MethodGet, "/webhook", legacy(getWebhook))
+	d.Add(http.MethodPut, "/webhook", legacy(updateWebhook))
+	d.Add(http.MethodDelete, "/webhook", legacy(deleteWebhook))
+	d.Add(http.MethodGet, "/webhook/deliveries", legacy(deliveries))
+	d.Add(http.MethodPost, "/webhook/deliveries/retry", legacy(retry))
+
+	return d
+}
+
+var dateTime = &openapi.Schema{Type: "string", Format: "date-time"}
+
+func pathParamSpec(name, desc string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "path", Description: desc, Required: true, Schema: &openapi.Schema{Type: "string"}}
+}
+
+func queryParamSpec(name, desc string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: desc, Schema: schema}
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}}}
+}
+
+func jsonResponse(desc string, schema *openapi.Schema) *openapi.Response {
+	return &openapi.Response{Description: desc, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}}}
+}
+
+func binaryResponse(desc string) *openapi.Response {
+	return &openapi.Response{
+		Description: desc,
+		Content:     map[string]openapi.MediaType{"*/*": {Schema: &openapi.Schema{Type: "string", ContentMediaType: "application/octet-stream"}}},
+	}
+}
+
+// problemResponse describes an application/problem+json error. OpenAPI
+// registers the Problem schema it refers to.
+func problemResponse(desc string) *openapi.Response {
+	return &openapi.Response{Description: desc, Content: map[string]openapi.MediaType{problem.ContentType: {Schema: problemSchema}}}
+}
+
+var problemSchema = &openapi.Schema{Ref: "#/components/schemas/Problem"}
+
+// with returns a copy of responses with the given status/response pairs
+// added.
+func with(responses map[string]*openapi.Response, pairs ...any) map[string]*openapi.Response {
+	out := make(map[string]*openapi.Response, len(responses)+len(pairs)/2)
+	for k, v := range responses {
+		out[k] = v
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out[pairs[i].(string)] = pairs[i+1].(*openapi.Response)
+	}
+	return out
+}
+
+// legacy derives the operation of a deprecated query-string alias: path
+// parameters move to the query string.
+func legacy(op *openapi.Operation) *openapi.Operation {
+	cp := *op
+	cp.OperationID = op.OperationID + "Legacy"
+	cp.Deprecated = true
+	cp.Parameters = make([]openapi.Parameter, len(op.Parameters))
+	for i, p := range op.Parameters {
+		if p.In == "path" {
+			p.In = "query"
+		}
+		cp.Parameters[i] = p
+	}
+	cp.Responses = make(map[string]*openapi.Response, len(op.Responses))
+	for code, resp := range op.Responses {
+		cp.Responses[code] = withDeprecationHeaders(resp)
+	}
+	return &cp
+}
+
+func withDeprecationHeaders(resp *openapi.Response) *openapi.Response {
+	cp := *resp
+	cp.Headers = map[string]openapi.Header{
+		"Deprecation": {Description: "RFC 9745 deprecation date", Schema: &openapi.Schema{Type: "string"}},
+		"Link":        {Description: "Successor route", Schema: &openapi.Schema{Type: "string"}},
+	}
+	for name, h := range resp.Headers {
+		cp.Headers[name] = h
+	}
+	return &cp
+}
+
+// conditionalParams are the request headers of a conditional GET.
+var conditionalParams = []openapi.Parameter{
+	{Name: "If-None-Match", In: "header", Description: "ETags the client holds; 304 if one is current", Schema: &openapi.Schema{Type: "string"}},
+	{Name: "If-Modified-Since", In: "header", Description: "304 if unchanged since; ignored with If-None-Match", Schema: &openapi.Schema{Type: "string"}},
+}
+
+var validatorHeaders = map[string]openapi.Header{
+	"ETag":          {Description: "Strong validator of this representation", Schema: &openapi.Schema{Type: "string"}},
+	"Last-Modified": {Description: "When the resource last changed", Schema: &openapi.Schema{Type: "string"}},
+	"Cache-Control": {Description: "How long the response may be reused", Schema: &openapi.Schema{Type: "string"}},
+}
+
+// cached adds the validator and Cache-Control headers to resp.
+func cached(resp *openapi.Response) *openapi.Response {
+	cp := *resp
+	cp.Headers = validatorHeaders
+	return &cp
+}
+
+var notModifiedResponse = &openapi.Response{Description: "Not modified", Headers: validatorHeaders}
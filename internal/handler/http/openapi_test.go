@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func stubRoutes() []Route {
+	stub := http.NotFoundHandler()
+	return Routes(Handlers{
+		Gateway:    stub,
+		Attachment: &AttachmentHandler{},
+		External:   &ExternalHandler{},
+		Health:     &HealthHandler{},
+		Docs:       &DocsHandler{},
+		Metrics:    stub,
+	})
+}
+
+// TestOpenAPIMatchesRoutes fails when a route is added or removed without
+// updating OpenAPI, or the other way round.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	if err := CheckSpec(OpenAPI(), stubRoutes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckSpecReportsDrift(t *testing.T) {
+	routes := stubRoutes()
+	dropped := routes[len(routes)-1]
+	routes = append(routes[:len(routes)-1], Route{Method: http.MethodGet, Path: "/undocumented"})
+
+	err := CheckSpec(OpenAPI(), routes)
+	if err == nil {
+		t.Fatal("CheckSpec = nil, want drift")
+	}
+	for _, want := range []string{"undocumented route GET /undocumented", "documented operation without a route " + dropped.Pattern()} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckSpec error %q does not mention %q", err, want)
+		}
+	}
+}
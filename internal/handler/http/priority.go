@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"simple-crud/internal/loadshed"
+)
+
+// Priorities maps each route pattern to its load-shedding priority, for
+// middleware_http.LoadShedMiddleware.
+func Priorities(routes []Route) map[string]loadshed.Priority {
+	out := make(map[string]loadshed.Priority, len(routes))
+	for _, rt := range routes {
+		out[rt.Pattern()] = routePriority(rt)
+	}
+	return out
+}
+
+// routePriority keeps health checks and metrics scrapes, then writes, ahead of reads, and sheds
+// bulk reads (the full product listing, reports and the external fetch)
+// first.
+func routePriority(rt Route) loadshed.Priority {
+	switch {
+	case rt.Path == "/healthz", rt.Path == "/livez", rt.Path == "/readyz", rt.Path == "/startupz", rt.Path == "/metrics":
+		return loadshed.PriorityCritical
+	case rt.Method != http.MethodGet && rt.Method != http.MethodHead:
+		return loadshed.PriorityHigh
+	case rt.Path == "/products", strings.HasPrefix(rt.Path, "/reports/"), rt.Path == "/external":
+		return loadshed.PriorityLow
+	default:
+		return loadshed.PriorityNormal
+	}
+}
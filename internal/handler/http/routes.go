@@ -0,0 +1,207 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Route is one method + path registered on the ServeMux. Path uses Go 1.22
+// pattern syntax, so wildcards like {id} are read with r.PathValue.
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+	// Successor is set on deprecated aliases and names the route that
+	// replaces them.
+	Successor string
+	// Public routes are served without a bearer token when authentication
+	// is enabled; see RequireAuth.
+	Public bool
+	// CacheControl is sent with successful and 304 responses unless the
+	// handler sets its own.
+	CacheControl string
+}
+
+// Cache-Control of the product reads. Listings are revalidated on every use,
+// which the catalog's change marker makes cheap; a product may be reused
+// for a few seconds.
+const (
+	productsCacheControl = "private, no-cache"
+	productCacheControl  = "private, max-age=5, must-revalidate"
+)
+
+// Pattern is the ServeMux pattern for the route, e.g. "GET /products/{id}".
+func (rt Route) Pattern() string {
+	return rt.Method + " " + rt.Path
+}
+
+func (rt Route) Deprecated() bool {
+	return rt.Successor != ""
+}
+
+// Handlers groups everything the HTTP API serves.
+type Handlers struct {
+	// Gateway transcodes the routes bound in product.proto to the gRPC
+	// services; see NewGateway.
+	Gateway    http.Handler
+	Attachment *AttachmentHandler
+	External   *ExternalHandler
+	Health     *HealthHandler
+	Docs       *DocsHandler
+	// Metrics serves the Prometheus metrics; see metrics.Handler.
+	Metrics http.Handler
+}
+
+// Routes lists every route of the HTTP API: the resource routes first, then
+// the query-string routes they replace, kept as deprecated aliases.
+func Routes(h Handlers) []Route {
+	gw := h.Gateway.ServeHTTP
+	return []Route{
+		{Method: http.MethodGet, Path: "/{$}", Handler: Root, Public: true},
+		{Method: http.MethodGet, Path: "/openapi.json", Handler: h.Docs.Spec, Public: true},
+		{Method: http.MethodGet, Path: "/docs", Handler: h.Docs.UI, Public: true},
+
+		{Method: http.MethodGet, Path: "/products", Handler: gw, CacheControl: productsCacheControl},
+		{Method: http.MethodPost, Path: "/products", Handler: gw},
+		{Method: http.MethodGet, Path: "/products/{id}", Handler: gw, CacheControl: productCacheControl},
+		{Method: http.MethodPut, Path: "/products/{id}", Handler: gw},
+		{Method: http.MethodDelete, Path: "/products/{id}", Handler: gw},
+
+		{Method: http.MethodGet, Path: "/products/{id}/images", Handler: h.Attachment.Download},
+		{Method: http.MethodPost, Path: "/products/{id}/images", Handler: h.Attachment.Upload},
+		{Method: http.MethodGet, Path: "/products/{id}/images/{file}", Handler: h.Attachment.Download},
+		{Method: http.MethodDelete, Path: "/products/{id}/images/{file}", Handler: h.Attachment.Delete},
+
+		{Method: http.MethodGet, Path: "/products/{id}/prices/schedules", Handler: gw},
+		{Method: http.MethodPost, Path: "/products/{id}/prices/schedules", Handler: gw},
+		{Method: http.MethodGet, Path: "/products/{id}/prices/history", Handler: gw},
+		{Method: http.MethodDelete, Path: "/prices/schedules/{schedule}", Handler: gw},
+
+		{Method: http.MethodGet, Path: "/reports/inventory-value", Handler: gw},
+		{Method: http.MethodGet, Path: "/reports/low-stock", Handler: gw},
+		{Method: http.MethodGet, Path: "/reports/price-histogram", Handler: gw},
+		{Method: http.MethodGet, Path: "/reports/counts", Handler: gw},
+
+		{Method: http.MethodGet, Path: "/webhooks", Handler: gw},
+		{Method: http.MethodPost, Path: "/webhooks", Handler: gw},
+		{Method: http.MethodGet, Path: "/webhooks/{id}", Handler: gw},
+		{Method: http.MethodPut, Path: "/webhooks/{id}", Handler: gw},
+		{Method: http.MethodDelete, Path: "/webhooks/{id}", Handler: gw},
+		{Method: http.MethodGet, Path: "/webhooks/{id}/deliveries", Handler: gw},
+		{Method: http.MethodPost, Path: "/webhooks/deliveries/{id}/retry", Handler: gw},
+
+		{Method: http.MethodGet, Path: "/admin/api-keys", Handler: gw},
+		{Method: http.MethodPost, Path: "/admin/api-keys", Handler: gw},
+		{Method: http.MethodGet, Path: "/admin/api-keys/{id}", Handler: gw},
+		{Method: http.MethodPost, Path: "/admin/api-keys/{id}/rotate", Handler: gw},
+		{Method: http.MethodDelete, Path: "/admin/api-keys/{id}", Handler: gw},
+
+		{Method: http.MethodGet, Path: "/external", Handler: h.External.Fetch},
+		{Method: http.MethodGet, Path: "/healthz", Handler: h.Health.Check, Public: true},
+		{Method: http.MethodGet, Path: "/livez", Handler: h.Health.Live, Public: true},
+		{Method: http.MethodGet, Path: "/readyz", Handler: h.Health.Ready, Public: true},
+		{Method: http.MethodGet, Path: "/startupz", Handler: h.Health.Startup, Public: true},
+		{Method: http.MethodGet, Path: "/metrics", Handler: h.Metrics.ServeHTTP, Public: true},
+
+		// Deprecated query-string routes
+		{Method: http.MethodGet, Path: "/product", Handler: fromQuery("/products/{id}", h.Gateway), Successor: "/products/{id}", CacheControl: productCacheControl},
+		{Method: http.MethodPost, Path: "/product", Handler: fromQuery("/products", h.Gateway), Successor: "/products"},
+		{Method: http.MethodPut, Path: "/product", Handler: fromQuery("/products/{id}", h.Gateway), Successor: "/products/{id}"},
+		{Method: http.MethodDelete, Path: "/product", Handler: fromQuery("/products/{id}", h.Gateway), Successor: "/products/{id}"},
+		{Method: http.MethodGet, Path: "/product/images", Handler: h.Attachment.Download, Successor: "/products/{id}/images/{file}"},
+		{Method: http.MethodPost, Path: "/product/images", Handler: h.Attachment.Upload, Successor: "/products/{id}/images"},
+		{Method: http.MethodDelete, Path: "/product/images", Handler: h.Attachment.Delete, Successor: "/products/{id}/images/{file}"},
+		{Method: http.MethodGet, Path: "/product/prices/schedules", Handler: fromQuery("/products/{id}/prices/schedules", h.Gateway), Successor: "/products/{id}/prices/schedules"},
+		{Method: http.MethodPost, Path: "/product/prices/schedules", Handler: fromQuery("/products/{id}/prices/schedules", h.Gateway), Successor: "/products/{id}/prices/schedules"},
+		{Method: http.MethodDelete, Path: "/product/prices/schedules", Handler: fromQuery("/prices/schedules/{schedule}", h.Gateway), Successor: "/prices/schedules/{schedule}"},
+		{Method: http.MethodGet, Path: "/product/prices/history", Handler: fromQuery("/products/{id}/prices/history", h.Gateway), Successor: "/products/{id}/prices/history"},
+		{Method: http.MethodGet, Path: "/webhook", Handler: fromQuery("/webhooks/{id}", h.Gateway), Successor: "/webhooks/{id}"},
+		{Method: http.MethodPut, Path: "/webhook", Handler: fromQuery("/webhooks/{id}", h.Gateway), Successor: "/webhooks/{id}"},
+		{Method: http.MethodDelete, Path: "/webhook", Handler: fromQuery("/webhooks/{id}", h.Gateway), Successor: "/webhooks/{id}"},
+		{Method: http.MethodGet, Path: "/webhook/deliveries", Handler: fromQuery("/webhooks/{id}/deliveries", h.Gateway), Successor: "/webhooks/{id}/deliveries"},
+		{Method: http.MethodPost, Path: "/webhook/deliveries/retry", Handler: fromQuery("/webhooks/deliveries/{id}/retry", h.Gateway), Successor: "/webhooks/deliveries/{id}/retry"},
+	}
+}
+
+// legacyRoutesDeprecatedAt is when the query-string routes were deprecated,
+// sent as an RFC 9745 Deprecation header.
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// Register adds routes to mux. Method-qualified patterns let the mux answer
+// 405 with an Allow header, so handlers no longer switch on r.Method.
+func Register(mux *http.ServeMux, routes []Route) {
+	for _, rt := range routes {
+		h := rt.Handler
+		if rt.CacheControl != "" {
+			h = cacheControl(h, rt.CacheControl)
+		}
+		if rt.Deprecated() {
+			h = deprecated(h, rt.Successor)
+		}
+		mux.HandleFunc(rt.Pattern(), h)
+	}
+}
+
+func deprecated(next http.HandlerFunc, successor string) http.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(legacyRoutesDeprecatedAt.Unix(), 10)
+	link := "<" + successor + `>; rel="successor-version"`
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", deprecation)
+		w.Header().Add("Link", link)
+		next(w, r)
+	}
+}
+
+func cacheControl(next http.HandlerFunc, value string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(&cacheControlWriter{ResponseWriter: w, value: value}, r)
+	}
+}
+
+// cacheControlWriter adds Cache-Control once the status shows the response
+// is cacheable, so errors are never cached.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if (code < 300 || code == http.StatusNotModified) && w.Header().Get("Cache-Control") == "" {
+			w.Header().Set("Cache-Control", w.value)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Root answers the bare "/" path.
+func Root(w http.ResponseWriter, r *http.Request) {
+	resp := map[string]string{"data": "hello-world"}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// pathParam returns the named path wildcard, falling back to the query string
+// so the deprecated routes keep working with the same handlers.
+func pathParam(r *http.Request, name string) string {
+	if v := r.PathValue(name); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(name)
+}
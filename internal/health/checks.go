@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewRegistryFromConfig registers the checks of the servers' dependencies:
+// MongoDB, which readiness requires, and, when configured, the OTLP
+// collector, the remote log endpoint and the EXTERNAL_HTTP upstream, which
+// only degrade it: the API works without them.
+func NewRegistryFromConfig(cfg *config.Config, db *database.Mongo, state State) *Registry {
+	timeout := time.Duration(cfg.HealthCheckTimeoutMs) * time.Millisecond
+	ttl := time.Duration(cfg.HealthCheckCacheMs) * time.Millisecond
+	client := &http.Client{}
+
+	r := NewRegistry(state)
+	r.Register(Check{Name: "mongodb", Run: MongoPing(db.Client), Critical: true, Timeout: timeout, CacheTTL: ttl})
+	if cfg.RemoteTraceRpcURI != "" {
+		r.Register(Check{Name: "otlp", Run: TCPDial(cfg.RemoteTraceRpcURI), Timeout: timeout, CacheTTL: ttl})
+	}
+	if cfg.RemoteLogHttpURI != "" {
+		r.Register(Check{Name: "remote_log", Run: HTTPGet(client, cfg.RemoteLogHttpURI), Timeout: timeout, CacheTTL: ttl})
+	}
+	if cfg.ExternalHTTP != "" {
+		r.Register(Check{Name: "external_http", Run: HTTPGet(client, cfg.ExternalHTTP), Timeout: timeout, CacheTTL: ttl})
+	}
+	return r
+}
+
+// MongoPing checks that the primary (or whichever member the client's read
+// preference selects) answers a ping.
+func MongoPing(client *mongo.Client) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, nil)
+	}
+}
+
+// TCPDial checks that something accepts connections at addr (host:port, or
+// a URL whose host is used), for endpoints such as the OTLP gRPC collector
+// that have nothing cheaper to ask.
+func TCPDial(addr string) func(ctx context.Context) error {
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// HTTPGet checks that rawURL answers a GET without a server error. Any
+// status under 500 counts: a 404 or 405 still proves the upstream is
+// serving.
+func HTTPGet(client *http.Client, rawURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+// Package health keeps the registry of dependency checks behind the
+// liveness, readiness and startup probes.
+//
+// Liveness only asks whether the process should be restarted, so it runs
+// the checks flagged Liveness and nothing that a dependency outage could
+// fail. Readiness runs every check and turns false while the process is
+// starting or draining; a failing non-critical check only degrades it.
+// Startup runs the critical checks until they first pass, then stays up.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Probe names, as reported.
+const (
+	Liveness  = "liveness"
+	Readiness = "readiness"
+	Startup   = "startup"
+)
+
+// Statuses of a report and of each check in it.
+const (
+	StatusUp       = "UP"
+	StatusDegraded = "DEGRADED"
+	StatusDown     = "DOWN"
+)
+
+const (
+	defaultTimeout = 2 * time.Second
+	// lifecycleCheck reports the process starting or draining.
+	lifecycleCheck = "lifecycle"
+)
+
+// Check is a named dependency check.
+type Check struct {
+	Name string
+	// Run returns nil when the dependency is usable; it is cancelled after
+	// Timeout.
+	Run func(ctx context.Context) error
+	// Critical checks take readiness down when they fail; the others only
+	// mark it degraded.
+	Critical bool
+	// Liveness checks also run on /livez. Only use it for the process's own
+	// health: a failing liveness probe gets the process restarted.
+	Liveness bool
+	// Timeout bounds Run; zero means 2s.
+	Timeout time.Duration
+	// CacheTTL reuses a result for this long, so frequent probes do not
+	// load the dependency; zero runs the check on every probe.
+	CacheTTL time.Duration
+}
+
+// State is the process lifecycle as the probes see it; *lifecycle.App
+// implements it.
+type State interface {
+	Started() bool
+	Draining() bool
+}
+
+// Result is one check's outcome in a report.
+type Result struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	DurationMs int64  `json:"duration_ms"`
+	Cached     bool   `json:"cached,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is a probe's outcome.
+type Report struct {
+	Probe  string   `json:"probe"`
+	Status string   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// Up reports whether the probe passes; a degraded report passes.
+func (r Report) Up() bool {
+	return r.Status != StatusDown
+}
+
+// Registry runs the registered checks for each probe.
+type Registry struct {
+	state   State
+	mu      sync.RWMutex
+	checks  []*entry
+	started atomic.Bool
+}
+
+type entry struct {
+	Check
+
+	mu     sync.Mutex
+	at     time.Time
+	err    error
+	tookMs int64
+}
+
+// NewRegistry returns an empty registry; state may be nil when nothing
+// manages the process's lifecycle.
+func NewRegistry(state State) *Registry {
+	return &Registry{state: state}
+}
+
+// Register adds a check. A check registered under an existing name
+// replaces it.
+func (r *Registry) Register(c Check) {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range r.checks {
+		if e.Name == c.Name {
+			r.checks[i] = &entry{Check: c}
+			return
+		}
+	}
+	r.checks = append(r.checks, &entry{Check: c})
+}
+
+// Live runs the liveness checks.
+func (r *Registry) Live(ctx context.Context) Report {
+	return r.run(ctx, Liveness, func(e *entry) bool { return e.Liveness })
+}
+
+// Ready runs every check, and is down while the process is starting or
+// draining.
+func (r *Registry) Ready(ctx context.Context) Report {
+	rep := r.run(ctx, Readiness, func(*entry) bool { return true })
+	if r.state != nil {
+		switch {
+		case r.state.Draining():
+			rep.down(Result{Name: lifecycleCheck, Status: StatusDown, Critical: true, Error: "shutting down"})
+		case !r.state.Started():
+			rep.down(Result{Name: lifecycleCheck, Status: StatusDown, Critical: true, Error: "starting"})
+		}
+	}
+	return rep
+}
+
+// Startup runs the critical checks until they first all pass, and passes
+// from then on without running them.
+func (r *Registry) Startup(ctx context.Context) Report {
+	if r.started.Load() {
+		return Report{Probe: Startup, Status: StatusUp}
+	}
+	rep := r.run(ctx, Startup, func(e *entry) bool { return e.Critical })
+	if r.state != nil && !r.state.Started() {
+		rep.down(Result{Name: lifecycleCheck, Status: StatusDown, Critical: true, Error: "starting"})
+	}
+	if rep.Up() {
+		r.started.Store(true)
+	}
+	return rep
+}
+
+// run runs the selected checks concurrently and folds their results.
+func (r *Registry) run(ctx context.Context, probe string, selected func(*entry) bool) Report {
+	r.mu.RLock()
+	var entries []*entry
+	for _, e := range r.checks {
+		if selected(e) {
+			entries = append(entries, e)
+		}
+	}
+	r.mu.RUnlock()
+
+	rep := Report{Probe: probe, Status: StatusUp, Checks: make([]Result, len(entries))}
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rep.Checks[i] = e.result(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, res := range rep.Checks {
+		switch {
+		case res.Status == StatusUp:
+		case res.Critical:
+			rep.Status = StatusDown
+		case rep.Status == StatusUp:
+			rep.Status = StatusDegraded
+		}
+	}
+	return rep
+}
+
+func (rep *Report) down(res Result) {
+	rep.Checks = append(rep.Checks, res)
+	rep.Status = StatusDown
+}
+
+// result runs the check, or reuses its last result within CacheTTL.
+// Concurrent probes wait for the one run in progress.
+func (e *entry) result(ctx context.Context) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cached := !e.at.IsZero() && e.CacheTTL > 0 && time.Since(e.at) < e.CacheTTL
+	if !cached {
+		start := time.Now()
+		// The result is shared, so a probe that hangs up does not cancel it.
+		cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.Timeout)
+		err := e.Run(cctx)
+		cancel()
+		e.at, e.err, e.tookMs = time.Now(), err, time.Since(start).Milliseconds()
+	}
+
+	res := Result{Name: e.Name, Status: StatusUp, Critical: e.Critical, DurationMs: e.tookMs, Cached: cached}
+	if e.err != nil {
+		res.Status = StatusDown
+		res.Error = e.err.Error()
+	}
+	return res
+}
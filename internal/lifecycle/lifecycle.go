@@ -0,0 +1,236 @@
+// Package lifecycle starts and stops the components of a process in order.
+// Components register hooks; Run starts them in registration order, waits
+// for SIGINT or SIGTERM, drains, then stops them in reverse order, so
+// servers stop taking requests before the database, telemetry and log
+// shipper they depend on go away. A second signal exits at once.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/logger"
+)
+
+// Hook is a component's start and stop functions; either may be nil.
+type Hook struct {
+	Name string
+	// OnStart must not block: long-running work goes in a goroutine (see
+	// Go). An error aborts startup.
+	OnStart func(ctx context.Context) error
+	// OnStop releases the component; its context expires after Timeout.
+	OnStop func(ctx context.Context) error
+	// Timeout bounds OnStart and OnStop; zero means Config.HookTimeout.
+	Timeout time.Duration
+}
+
+type Config struct {
+	// Drain is how long shutdown waits, with Draining reporting true,
+	// before stopping anything, so load balancers stop routing to the
+	// process while it still serves.
+	Drain time.Duration
+	// HookTimeout bounds each hook unless it sets its own.
+	HookTimeout time.Duration
+}
+
+func NewConfig(cfg *config.Config) Config {
+	return Config{
+		Drain:       time.Duration(cfg.ShutdownDrainMs) * time.Millisecond,
+		HookTimeout: time.Duration(cfg.ShutdownHookTimeoutMs) * time.Millisecond,
+	}
+}
+
+// App runs the registered hooks. Register everything before Run.
+type App struct {
+	cfg      Config
+	hooks    []Hook
+	started  atomic.Bool
+	draining atomic.Bool
+	failed   chan error
+	failOnce sync.Once
+}
+
+func New(cfg Config) *App {
+	return &App{cfg: cfg, failed: make(chan error, 1)}
+}
+
+// Append registers a hook; hooks start in the order they are appended.
+func (a *App) Append(h Hook) {
+	a.hooks = append(a.hooks, h)
+}
+
+// Go registers a background loop: run starts with the other hooks and its
+// context is cancelled when the hook stops, which then waits for run to
+// return.
+func (a *App) Go(name string, run func(ctx context.Context)) {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	a.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// Fail shuts the process down because a component failed, e.g. a server
+// that stopped serving; Run then returns err.
+func (a *App) Fail(err error) {
+	a.failOnce.Do(func() { a.failed <- err })
+}
+
+// Started reports whether every hook has started.
+func (a *App) Started() bool {
+	return a.started.Load()
+}
+
+// Draining reports whether shutdown has begun, so readiness checks can
+// turn the process away from load balancers.
+func (a *App) Draining() bool {
+	return a.draining.Load()
+}
+
+// Run starts the hooks, blocks until a signal, ctx ending or Fail, then
+// drains and stops the started hooks in reverse order. It returns the
+// error that made it stop early, or the first stop error.
+func (a *App) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	started, err := a.start(ctx)
+	if err != nil {
+		logger.Error(ctx, "Startup failed",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+		)
+		return errors.Join(err, a.stop(ctx, started))
+	}
+	a.started.Store(true)
+	logger.Info(ctx, "Started", slog.Int("data.components", len(started)))
+
+	var cause error
+	select {
+	case sig := <-signals:
+		logger.Info(ctx, "Received shutdown signal; a second one exits at once",
+			slog.String("data.signal", sig.String()),
+			slog.Int64("data.drain_ms", a.cfg.Drain.Milliseconds()),
+		)
+	case <-ctx.Done():
+		logger.Info(ctx, "Shutting down", slog.String("data.reason", ctx.Err().Error()))
+	case cause = <-a.failed:
+		logger.Error(ctx, "Component failed, shutting down",
+			slog.String("exception.message", cause.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(cause))),
+		)
+	}
+	go forceExitOnSignal(ctx, signals)
+
+	a.draining.Store(true)
+	if cause == nil && a.cfg.Drain > 0 {
+		time.Sleep(a.cfg.Drain)
+	}
+	err = a.stop(ctx, started)
+	if cause != nil {
+		return errors.Join(cause, err)
+	}
+	if err == nil {
+		logger.Info(ctx, "Stopped cleanly")
+	}
+	return err
+}
+
+// start runs the OnStart hooks in order and returns the hooks to stop: all
+// of them, or, when one fails, those before it and the later stop-only
+// hooks, which release what main acquired before Run rather than anything
+// OnStart would have.
+func (a *App) start(ctx context.Context) ([]Hook, error) {
+	for i, h := range a.hooks {
+		if h.OnStart == nil {
+			continue
+		}
+		hctx, cancel := context.WithTimeout(ctx, a.timeout(h))
+		err := h.OnStart(hctx)
+		cancel()
+		if err != nil {
+			started := slices.Clone(a.hooks[:i])
+			for _, later := range a.hooks[i+1:] {
+				if later.OnStart == nil {
+					started = append(started, later)
+				}
+			}
+			return started, fmt.Errorf("start %s: %w", h.Name, err)
+		}
+	}
+	return a.hooks, nil
+}
+
+// stop runs the OnStop hooks in reverse order. A hook that fails or times
+// out is logged and the rest still stop.
+func (a *App) stop(ctx context.Context, hooks []Hook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		start := time.Now()
+		hctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.timeout(h))
+		err := h.OnStop(hctx)
+		cancel()
+		if err != nil {
+			logger.Error(ctx, "Component did not stop cleanly",
+				slog.String("data.component", h.Name),
+				slog.String("exception.message", err.Error()),
+				slog.String("exception.type", fmt.Sprintf("%T", errors.Unwrap(err))),
+			)
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+		logger.Info(ctx, "Component stopped",
+			slog.String("data.component", h.Name),
+			slog.Int64("data.duration_ms", time.Since(start).Milliseconds()),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+func (a *App) timeout(h Hook) time.Duration {
+	if h.Timeout > 0 {
+		return h.Timeout
+	}
+	return a.cfg.HookTimeout
+}
+
+func forceExitOnSignal(ctx context.Context, signals <-chan os.Signal) {
+	sig := <-signals
+	logger.Warn(ctx, "Received second signal, exiting without stopping",
+		slog.String("data.signal", sig.String()),
+	)
+	os.Exit(1)
+}
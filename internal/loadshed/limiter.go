@@ -0,0 +1,151 @@
+// Package loadshed bounds how many requests a server works on at once. The
+// bound adapts to observed latency (AIMD: it grows by one per window of fast
+// requests and shrinks by a tenth when they slow down), so when MongoDB
+// struggles the server sheds excess requests quickly instead of queueing
+// them. Lower priorities get a smaller share of the bound and are shed
+// first.
+package loadshed
+
+import (
+	"sync"
+	"time"
+
+	"simple-crud/internal/config"
+)
+
+// Priority ranks requests for shedding.
+type Priority int
+
+const (
+	// PriorityLow is for bulk reads: full listings, streams, reports.
+	PriorityLow Priority = iota
+	// PriorityNormal is for ordinary reads.
+	PriorityNormal
+	// PriorityHigh is for writes.
+	PriorityHigh
+	// PriorityCritical is for health checks, which are never shed.
+	PriorityCritical
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// share is the fraction of the limit each priority may fill.
+var share = map[Priority]float64{
+	PriorityLow:    0.5,
+	PriorityNormal: 0.8,
+	PriorityHigh:   1,
+}
+
+// backoff is the multiplicative decrease applied when latency exceeds the
+// target.
+const backoff = 0.9
+
+type Config struct {
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	// LatencyTarget is the latency above which a request counts as a sign
+	// of overload.
+	LatencyTarget time.Duration
+}
+
+func NewConfig(cfg *config.Config) Config {
+	return Config{
+		InitialLimit:  int(cfg.LoadShedInitialLimit),
+		MinLimit:      int(cfg.LoadShedMinLimit),
+		MaxLimit:      int(cfg.LoadShedMaxLimit),
+		LatencyTarget: time.Duration(cfg.LoadShedLatencyTargetMs) * time.Millisecond,
+	}
+}
+
+// Limiter is an adaptive concurrency limit. It is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+
+	mu           sync.Mutex
+	limit        float64
+	inflight     int
+	lastDecrease time.Time
+}
+
+func NewLimiter(cfg Config) *Limiter {
+	cfg.MinLimit = max(cfg.MinLimit, 1)
+	cfg.MaxLimit = max(cfg.MaxLimit, cfg.MinLimit)
+	return &Limiter{
+		cfg:   cfg,
+		limit: float64(min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit)),
+	}
+}
+
+// Token is an admitted request; Release it when the request is done.
+type Token struct {
+	l        *Limiter
+	start    time.Time
+	inflight int
+	sample   bool
+}
+
+// Acquire admits a request of priority p, or returns false when it should
+// be shed. Critical requests are always admitted.
+func (l *Limiter) Acquire(p Priority) (*Token, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p != PriorityCritical && float64(l.inflight) >= l.limit*share[p] {
+		return nil, false
+	}
+	l.inflight++
+	// Only ordinary requests adjust the limit: health checks say nothing
+	// about load, and bulk requests are slow by nature.
+	sample := p == PriorityNormal || p == PriorityHigh
+	return &Token{l: l, start: time.Now(), inflight: l.inflight, sample: sample}, true
+}
+
+// Release ends the request. overloaded marks a failure that signals
+// overload, such as a timeout, whatever its latency.
+func (t *Token) Release(overloaded bool) {
+	l := t.l
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inflight--
+	if !t.sample {
+		return
+	}
+	if overloaded || now.Sub(t.start) > l.cfg.LatencyTarget {
+		// Requests admitted together finish together; decrease once per
+		// latency target so one slow burst does not collapse the limit.
+		if now.Sub(l.lastDecrease) >= l.cfg.LatencyTarget {
+			l.limit = max(l.limit*backoff, float64(l.cfg.MinLimit))
+			l.lastDecrease = now
+		}
+		return
+	}
+	// Only grow while the limit is being used, or an idle server would
+	// drift to the maximum.
+	if float64(t.inflight)*2 >= l.limit {
+		l.limit = min(l.limit+1/l.limit, float64(l.cfg.MaxLimit))
+	}
+}
+
+// State returns the current limit and the requests in flight.
+func (l *Limiter) State() (limit, inflight int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit), l.inflight
+}
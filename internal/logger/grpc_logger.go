@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"simple-crud/internal/auth"
+
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -51,7 +53,8 @@ func msgAttrs(prefix string, m interface{}) []slog.Attr {
 	if pm, ok := m.(proto.Message); ok {
 		b, err := protojson.Marshal(pm)
 		if err == nil {
-			a, _ := jsonAttrsWithPrefix(prefix, b)
+			apiKey := pm.ProtoReflect().Descriptor().FullName() == apiKeyMessage
+			a, _ := jsonAttrsWithPrefix(prefix, b, apiKey)
 			return a
 		}
 	}
@@ -60,11 +63,14 @@ func msgAttrs(prefix string, m interface{}) []slog.Attr {
 }
 
 // jsonAttrsWithPrefix reuses existing flattenJSON logic but with custom prefix.
-func jsonAttrsWithPrefix(prefix string, b []byte) ([]slog.Attr, error) {
+func jsonAttrsWithPrefix(prefix string, b []byte, apiKey bool) ([]slog.Attr, error) {
 	var data any
 	if err := json.Unmarshal(b, &data); err != nil {
 		return []slog.Attr{slog.String(prefix, string(b))}, nil
 	}
+	if apiKey {
+		redactAPIKey(data)
+	}
 	attrs := make([]slog.Attr, 0, 8)
 	flattenJSON(prefix, data, &attrs)
 	return attrs, nil
@@ -79,6 +85,9 @@ func LogGRPCRequest(ctx context.Context, fullMethod string, md metadata.MD, req
 		slog.String("grpc.direction", direction),
 		slog.String("grpc.method", fullMethod),
 	}
+	if sub := auth.Subject(ctx); sub != "" {
+		attrs = append(attrs, slog.String("enduser.id", sub))
+	}
 	attrs = append(attrs, MetadataAttrs(md)...)
 	attrs = append(attrs, msgAttrs("grpc.request", req)...)
 	return attrs
@@ -13,6 +13,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+
+	"simple-crud/internal/auth"
 )
 
 // MaxBodyLogged limits what we read. 1 << 20 = 1 MiB.
@@ -28,7 +30,8 @@ var allowedHeaders = map[string]bool{
 	"set-cookie":     true,
 }
 
-// CaptureBody reads r.Body up to MaxBodyLogged bytes, closes it, and returns a new reader.
+// CaptureBody reads r.Body up to MaxBodyLogged bytes and puts the whole body
+// back, so handlers still see (and size-check) what the client sent.
 func CaptureBody(r *http.Request) ([]byte, error) {
 	if r.Body == nil {
 		return nil, nil // nothing to capture
@@ -37,11 +40,19 @@ func CaptureBody(r *http.Request) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	_ = r.Body.Close()
-	r.Body = io.NopCloser(bytes.NewReader(body)) // Reset readable stream
+	replayBody(r, body)
 	return body, nil
 }
 
+// replayBody makes r.Body yield the already read prefix, then the rest of
+// the original body.
+func replayBody(r *http.Request, prefix []byte) {
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+}
+
 func HeaderAttrs(hdr http.Header) []slog.Attr {
 	attrs := make([]slog.Attr, 0, len(hdr))
 	for name, values := range hdr {
@@ -60,6 +71,12 @@ func HeaderAttrs(hdr http.Header) []slog.Attr {
 
 // DecodeBody inspects r.Body, produces slog.Attrs, and puts a *copy* back.
 func DecodeBody(contentType string, body []byte) ([]slog.Attr, error) {
+	return decodeBody(contentType, body, false)
+}
+
+// decodeBody is DecodeBody for a body that, if apiKey, is an ApiKey whose
+// top-level key must be redacted.
+func decodeBody(contentType string, body []byte, apiKey bool) ([]slog.Attr, error) {
 	if len(body) == 0 {
 		return nil, nil
 	}
@@ -67,7 +84,7 @@ func DecodeBody(contentType string, body []byte) ([]slog.Attr, error) {
 	ct, _, _ := mime.ParseMediaType(contentType)
 	switch ct {
 	case "application/json":
-		return jsonAttrs(body)
+		return jsonAttrs(body, apiKey)
 	case "application/x-www-form-urlencoded":
 		return formAttrs(body)
 	default:
@@ -95,8 +112,7 @@ func BodyAttrs(r *http.Request) ([]slog.Attr, error) {
 	if err != nil {
 		return nil, err
 	}
-	_ = r.Body.Close()
-	r.Body = io.NopCloser(bytes.NewBuffer(body)) // hand it downstream intact
+	replayBody(r, body) // hand it downstream intact
 
 	// 👉 Skip logging if body is truly empty
 	if len(body) == 0 {
@@ -107,7 +123,7 @@ func BodyAttrs(r *http.Request) ([]slog.Attr, error) {
 	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 	switch ct {
 	case "application/json":
-		return jsonAttrs(body)
+		return jsonAttrs(body, false)
 	case "application/x-www-form-urlencoded":
 		return formAttrs(body)
 	default:
@@ -118,18 +134,27 @@ func BodyAttrs(r *http.Request) ([]slog.Attr, error) {
 // ---------- Helpers ------------------------------------------------------
 
 // For application/json
-func jsonAttrs(b []byte) ([]slog.Attr, error) {
+func jsonAttrs(b []byte, apiKey bool) ([]slog.Attr, error) {
 	var data any
 	if err := json.Unmarshal(b, &data); err != nil {
 		// Not valid JSON – log string.
 		return []slog.Attr{slog.String("http.body", string(b))}, nil
 	}
+	if apiKey {
+		redactAPIKey(data)
+	}
 	attrs := make([]slog.Attr, 0, 8)
 	flattenJSON("http.body", data, &attrs)
 	return attrs, nil
 }
 
 func flattenJSON(prefix string, v any, dst *[]slog.Attr) {
+	if sensitiveField(prefix[strings.LastIndexByte(prefix, '.')+1:]) {
+		if v != nil {
+			*dst = append(*dst, slog.String(prefix, "***"))
+		}
+		return
+	}
 	switch t := v.(type) {
 	case map[string]any:
 		for k, v2 := range t {
@@ -167,6 +192,10 @@ func formAttrs(b []byte) ([]slog.Attr, error) {
 	}
 	attrs := make([]slog.Attr, 0, len(vals))
 	for k, v := range vals {
+		if sensitiveField(k) {
+			attrs = append(attrs, slog.String("http.body."+k, "***"))
+			continue
+		}
 		attrs = append(attrs, slog.String("http.body."+k, redactIfNeeded(strings.Join(v, ", "))))
 	}
 	return attrs, nil
@@ -187,6 +216,32 @@ func binaryAttrs(b []byte) []slog.Attr {
 	}
 }
 
+// sensitiveFields are body fields logged as "***" wherever they appear,
+// e.g. webhook signing secrets.
+var sensitiveFields = map[string]bool{
+	"password": true,
+	"secret":   true,
+}
+
+func sensitiveField(name string) bool {
+	return sensitiveFields[strings.ToLower(name)]
+}
+
+// apiKeyPath prefixes the HTTP routes answering with an ApiKey, whose
+// top-level key is the plaintext key after create and rotate. "key" is too
+// common a name to redact everywhere (report groups have one).
+const apiKeyPath = "/admin/api-keys"
+
+// apiKeyMessage is the gRPC counterpart of apiKeyPath.
+const apiKeyMessage = "product.ApiKey"
+
+// redactAPIKey replaces the key of a decoded ApiKey with "***".
+func redactAPIKey(data any) {
+	if m, ok := data.(map[string]any); ok && m["key"] != nil {
+		m["key"] = "***"
+	}
+}
+
 // Very naive example redactor; plug real regexps/HashiCorp Vault here.
 func redactIfNeeded(s string) string {
 	if strings.Contains(strings.ToLower(s), "password") {
@@ -203,6 +258,9 @@ func LogHTTPRequest(ctx context.Context, r *http.Request, direction string) []sl
 		slog.String("http.method", r.Method),
 		slog.String("http.path", r.URL.Path),
 	}
+	if sub := auth.Subject(ctx); sub != "" {
+		attrs = append(attrs, slog.String("enduser.id", sub))
+	}
 
 	attrs = append(attrs, HeaderAttrs(r.Header)...)
 	attrs = append(attrs, QueryAttrs(r.URL.Query())...)
@@ -236,7 +294,8 @@ func LogHTTPResponse(ctx context.Context, req *http.Request, response_header htt
 	if response_body != nil {
 		buf := new(bytes.Buffer)
 		if _, err := io.Copy(buf, response_body); err == nil && buf.Len() > 0 {
-			bAttrs, err := DecodeBody(response_header.Get("Content-Type"), buf.Bytes())
+			apiKey := strings.HasPrefix(req.URL.Path, apiKeyPath)
+			bAttrs, err := decodeBody(response_header.Get("Content-Type"), buf.Bytes(), apiKey)
 			if err == nil {
 				attrs = append(attrs, bAttrs...)
 			} else {
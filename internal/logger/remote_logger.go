@@ -2,16 +2,20 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
 var (
 	httpClient *http.Client
+	// inflight counts the entries being shipped, so Flush can wait for them.
+	inflight atomic.Int64
 )
 
 func init() {
@@ -22,7 +26,9 @@ func init() {
 
 // sendLog sends log entry in background
 func sendLog(level, message string, attrs []slog.Attr) {
+	inflight.Add(1)
 	go func() {
+		defer inflight.Add(-1)
 		remoteURI := os.Getenv("REMOTE_LOG_HTTP_URI")
 		if remoteURI == "" {
 			return
@@ -57,3 +63,23 @@ func sendLog(level, message string, attrs []slog.Attr) {
 		}
 	}()
 }
+
+// Pending returns how many entries are still being shipped.
+func Pending() int64 {
+	return inflight.Load()
+}
+
+// Flush waits until the entries logged so far are shipped, or ctx expires.
+// It is the last thing to stop, so the shutdown itself is logged remotely.
+func Flush(ctx context.Context) error {
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	for inflight.Load() > 0 {
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package metrics holds the Prometheus metrics the servers expose on
+// /metrics: RED metrics (rate, errors, duration) per HTTP route and gRPC
+// method, recorded by the tracing middleware and interceptors, MongoDB
+// command latencies, the remote log backlog, Go runtime metrics and build
+// info.
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/version"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// namespace prefixes every metric of ours, keeping them apart from the Go
+// and process collectors' and from other services scraped alongside.
+const namespace = "simple_crud"
+
+// Registry holds every metric here; it is not the global default registry,
+// so dependencies cannot add metrics behind our back.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_server_requests_total",
+		Help:      "HTTP requests served, by method, route pattern and status code.",
+	}, []string{"method", "route", "code"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_server_request_duration_seconds",
+		Help:      "Time to serve HTTP requests, by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	grpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handled_total",
+		Help:      "gRPC calls completed, by service, method, type and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_type", "grpc_code"})
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Time to handle gRPC calls, by service, method and type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_type"})
+
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongodb_client_command_duration_seconds",
+		Help:      "Time for MongoDB commands to complete, by command name and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "outcome"})
+
+	buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Always 1; the labels identify the running build.",
+	}, []string{"version", "commit", "build_time", "goversion"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		grpcHandled, grpcDuration,
+		mongoDuration,
+		buildInfo,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "remote_log_pending",
+			Help:      "Log entries waiting to be shipped to the remote log endpoint.",
+		}, func() float64 { return float64(logger.Pending()) }),
+	)
+	buildInfo.WithLabelValues(version.Version, version.Commit, version.BuildTime, runtime.Version()).Set(1)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// httpMethods are the methods recorded by name; clients choose the method,
+// so any other counts as "OTHER" to keep the label bounded.
+var httpMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// ObserveHTTP records a served request. route is the matched pattern
+// ("/products/{id}"), never the raw path, to keep the label bounded;
+// requests that matched no route count as "unmatched".
+func ObserveHTTP(method, route string, code int, d time.Duration) {
+	if !httpMethods[method] {
+		method = "OTHER"
+	}
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(d.Seconds())
+}
+
+// gRPC call types, as in grpc_type.
+const (
+	Unary        = "unary"
+	ServerStream = "server_stream"
+	ClientStream = "client_stream"
+	BidiStream   = "bidi_stream"
+)
+
+// ObserveGRPC records a completed call to fullMethod
+// ("/package.Service/Method").
+func ObserveGRPC(fullMethod, callType string, code codes.Code, d time.Duration) {
+	service, method := splitMethod(fullMethod)
+	grpcHandled.WithLabelValues(service, method, callType, code.String()).Inc()
+	grpcDuration.WithLabelValues(service, method, callType).Observe(d.Seconds())
+}
+
+// ObserveMongo records a completed MongoDB command.
+func ObserveMongo(command string, failed bool, d time.Duration) {
+	outcome := "success"
+	if failed {
+		outcome = "failure"
+	}
+	mongoDuration.WithLabelValues(command, outcome).Observe(d.Seconds())
+}
+
+func splitMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(fullMethod, '/'); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
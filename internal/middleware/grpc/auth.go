@@ -0,0 +1,149 @@
+package middleware_grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"simple-crud/internal/auth"
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethodPrefixes are served without a principal: reflection and the
+// health service are used by tooling and probes that carry no credentials.
+var publicMethodPrefixes = []string{
+	"/grpc.reflection.",
+	"/grpc.health.",
+}
+
+// methodScopes is the scope each method needs; methods missing here need
+// admin. It mirrors the HTTP routes (see handler.RequireAuth).
+var methodScopes = map[string]string{
+	pb.ProductService_GetAll_FullMethodName:         auth.ScopeProductsRead,
+	pb.ProductService_GetByID_FullMethodName:        auth.ScopeProductsRead,
+	pb.ProductService_StreamProducts_FullMethodName: auth.ScopeProductsRead,
+	pb.ProductService_Create_FullMethodName:         auth.ScopeProductsWrite,
+	pb.ProductService_Update_FullMethodName:         auth.ScopeProductsWrite,
+	pb.ProductService_Delete_FullMethodName:         auth.ScopeProductsWrite,
+
+	pb.ReportService_InventoryValue_FullMethodName: auth.ScopeProductsRead,
+	pb.ReportService_LowStock_FullMethodName:       auth.ScopeProductsRead,
+	pb.ReportService_PriceHistogram_FullMethodName: auth.ScopeProductsRead,
+	pb.ReportService_CountBy_FullMethodName:        auth.ScopeProductsRead,
+
+	pb.PriceService_ListSchedules_FullMethodName:  auth.ScopeProductsRead,
+	pb.PriceService_GetHistory_FullMethodName:     auth.ScopeProductsRead,
+	pb.PriceService_CreateSchedule_FullMethodName: auth.ScopeProductsWrite,
+	pb.PriceService_CancelSchedule_FullMethodName: auth.ScopeProductsWrite,
+}
+
+func requiredScope(fullMethod string) string {
+	if scope, ok := methodScopes[fullMethod]; ok {
+		return scope
+	}
+	return auth.ScopeAdmin
+}
+
+// UnaryAuthInterceptor verifies the credentials in the "x-api-key" or
+// "authorization" metadata and attaches the principal to the context. It
+// rejects nothing; UnaryRequireAuthInterceptor does. It belongs first in the
+// chain, before UnaryTracingInterceptor, so the request log carries the
+// subject.
+func UnaryAuthInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(authenticate(ctx, authenticator), req)
+	}
+}
+
+// StreamAuthInterceptor is the streaming counterpart of UnaryAuthInterceptor.
+func StreamAuthInterceptor(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: authenticate(ss.Context(), authenticator)})
+	}
+}
+
+// UnaryRequireAuthInterceptor fails calls without a principal with
+// Unauthenticated, and calls whose credentials lack the method's scope with
+// PermissionDenied. It goes after UnaryTracingInterceptor so the rejection
+// is traced and logged.
+func UnaryRequireAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := requireAuth(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequireAuthInterceptor is the streaming counterpart of
+// UnaryRequireAuthInterceptor.
+func StreamRequireAuthInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := requireAuth(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authenticate(ctx context.Context, authenticator *auth.Authenticator) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization, apiKey := firstValue(md, "authorization"), firstValue(md, "x-api-key")
+	if authorization == "" && apiKey == "" {
+		return ctx
+	}
+	// Join the caller's trace, so a key lookup is not a trace of its own.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, telemetry.MetadataTextMapCarrier(md))
+	return authenticator.Authenticate(ctx, authorization, apiKey)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func requireAuth(ctx context.Context, fullMethod string) error {
+	for _, prefix := range publicMethodPrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return nil
+		}
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		if scope := requiredScope(fullMethod); !p.HasScope(scope) {
+			return status.Error(codes.PermissionDenied, "credentials lack the "+scope+" scope")
+		}
+		return nil
+	}
+
+	err := auth.ErrorFromContext(ctx)
+	switch {
+	case errors.Is(err, auth.ErrMissingToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, auth.ErrInvalidToken):
+		// Record why the credentials were rejected on the span, but do not
+		// echo it.
+		trace.SpanFromContext(ctx).RecordError(err)
+		return status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Error())
+	default:
+		trace.SpanFromContext(ctx).RecordError(err)
+		return status.Error(codes.Unavailable, "credentials could not be verified")
+	}
+}
+
+// contextServerStream overrides Context with one carrying the principal.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context { return s.ctx }
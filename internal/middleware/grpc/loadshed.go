@@ -0,0 +1,125 @@
+package middleware_grpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/loadshed"
+	"simple-crud/internal/logger"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// methodPriorities mirrors the HTTP route priorities (see
+// handler.Priorities): writes are high, bulk reads low, and methods missing
+// here normal, except health checks, which are never shed.
+var methodPriorities = map[string]loadshed.Priority{
+	pb.ProductService_GetAll_FullMethodName:         loadshed.PriorityLow,
+	pb.ProductService_StreamProducts_FullMethodName: loadshed.PriorityLow,
+	pb.ProductService_Create_FullMethodName:         loadshed.PriorityHigh,
+	pb.ProductService_Update_FullMethodName:         loadshed.PriorityHigh,
+	pb.ProductService_Delete_FullMethodName:         loadshed.PriorityHigh,
+
+	pb.ReportService_InventoryValue_FullMethodName: loadshed.PriorityLow,
+	pb.ReportService_LowStock_FullMethodName:       loadshed.PriorityLow,
+	pb.ReportService_PriceHistogram_FullMethodName: loadshed.PriorityLow,
+	pb.ReportService_CountBy_FullMethodName:        loadshed.PriorityLow,
+
+	pb.PriceService_CreateSchedule_FullMethodName: loadshed.PriorityHigh,
+	pb.PriceService_CancelSchedule_FullMethodName: loadshed.PriorityHigh,
+
+	pb.WebhookService_CreateSubscription_FullMethodName: loadshed.PriorityHigh,
+	pb.WebhookService_UpdateSubscription_FullMethodName: loadshed.PriorityHigh,
+	pb.WebhookService_DeleteSubscription_FullMethodName: loadshed.PriorityHigh,
+	pb.WebhookService_RetryDelivery_FullMethodName:      loadshed.PriorityHigh,
+
+	pb.ApiKeyService_CreateKey_FullMethodName: loadshed.PriorityHigh,
+	pb.ApiKeyService_RotateKey_FullMethodName: loadshed.PriorityHigh,
+	pb.ApiKeyService_RevokeKey_FullMethodName: loadshed.PriorityHigh,
+}
+
+func methodPriority(fullMethod string) loadshed.Priority {
+	if strings.HasPrefix(fullMethod, "/grpc.health.") {
+		return loadshed.PriorityCritical
+	}
+	if p, ok := methodPriorities[fullMethod]; ok {
+		return p
+	}
+	return loadshed.PriorityNormal
+}
+
+// UnaryLoadShedInterceptor admits each call through the adaptive concurrency
+// limiter, mirroring middleware_http.LoadShedMiddleware: shed calls fail at
+// once with Unavailable, and DeadlineExceeded or Unavailable from the
+// handler counts as overload. It goes after tracing, whose span records the
+// shedding, and after rate limiting.
+func UnaryLoadShedInterceptor(limiter *loadshed.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token, err := admit(ctx, limiter, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		token.Release(overloaded(err))
+		return resp, err
+	}
+}
+
+// StreamLoadShedInterceptor is the streaming counterpart of
+// UnaryLoadShedInterceptor; a stream holds its place until it ends. Health
+// Watch streams are let through uncounted: they stay open for as long as
+// the client is connected.
+func StreamLoadShedInterceptor(limiter *loadshed.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.FullMethod == healthpb.Health_Watch_FullMethodName {
+			return handler(srv, ss)
+		}
+		token, err := admit(ss.Context(), limiter, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		err = handler(srv, ss)
+		token.Release(overloaded(err))
+		return err
+	}
+}
+
+func admit(ctx context.Context, limiter *loadshed.Limiter, fullMethod string) (*loadshed.Token, error) {
+	priority := methodPriority(fullMethod)
+	token, ok := limiter.Acquire(priority)
+	if ok {
+		return token, nil
+	}
+
+	limit, inflight := limiter.State()
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Bool("loadshed.shed", true))
+	span.AddEvent("request shed", trace.WithAttributes(
+		attribute.String("loadshed.priority", priority.String()),
+		attribute.Int("loadshed.limit", limit),
+		attribute.Int("loadshed.inflight", inflight),
+	))
+	logger.Warn(ctx, "Request shed",
+		slog.String("data.route", fullMethod),
+		slog.String("data.priority", priority.String()),
+		slog.Int("data.limit", limit),
+		slog.Int("data.inflight", inflight),
+	)
+	return nil, status.Error(codes.Unavailable, "server is overloaded; retry shortly")
+}
+
+func overloaded(err error) bool {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,20 @@
+package middleware_grpc
+
+import (
+	"context"
+
+	"simple-crud/internal/database"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryMongoSessionInterceptor attaches a causally consistent MongoDB session
+// to each unary call, mirroring middleware_http.MongoSessionMiddleware.
+func UnaryMongoSessionInterceptor(db *database.Mongo) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, end := db.WithCausalSession(ctx)
+		defer end()
+
+		return handler(ctx, req)
+	}
+}
@@ -2,9 +2,11 @@ package middleware_grpc
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"simple-crud/internal/logger"
+	"simple-crud/internal/metrics"
 	"simple-crud/internal/telemetry"
 
 	"go.opentelemetry.io/otel"
@@ -22,8 +24,9 @@ var tracer = otel.Tracer("GrpcMiddleware")
 // UnaryTracingInterceptor returns a gRPC unary server interceptor that
 // 1) propagates/creates spans,
 // 2) logs request & response via logger.LogGRPCRequest/Response,
-// 3) injects X-Trace-ID trailer, and
-// 4) handles panic recovery consistently.
+// 3) injects X-Trace-ID trailer,
+// 4) records the call's metrics, and
+// 5) handles panic recovery consistently.
 func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		// Extract existing tracing headers
@@ -87,6 +90,7 @@ func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
 			grpcCode = grpcCodes.OK // success: 0
 		}
 		status := int32(grpcCode)
+		metrics.ObserveGRPC(info.FullMethod, metrics.Unary, grpcCode, duration)
 
 		respAttrs := logger.LogGRPCResponse(
 			ctx,
@@ -105,6 +109,98 @@ func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// tracedServerStream overrides Context so handlers see the span, and counts
+// the messages sent for the response log.
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent int64
+}
+
+func (s *tracedServerStream) Context() context.Context { return s.ctx }
+
+func (s *tracedServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+// StreamTracingInterceptor is the streaming counterpart of
+// UnaryTracingInterceptor: same span, logging, metrics, trailer and panic
+// handling, with the number of sent messages logged instead of the response
+// body.
+func StreamTracingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := ss.Context()
+		md, _ := metadata.FromIncomingContext(ctx)
+		carrier := telemetry.MetadataTextMapCarrier(md)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+		ctx, span := tracer.Start(ctx, info.FullMethod)
+		start := time.Now()
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				span.RecordError(errFromRecover(rec))
+				span.SetStatus(codes.Error, "panic occurred")
+				panic(rec)
+			}
+			span.End()
+		}()
+
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+
+		reqAttrs := logger.LogGRPCRequest(ctx, info.FullMethod, md, nil, "incoming::request")
+		logger.Info(ctx, "GRPC", reqAttrs...)
+
+		wrapped := &tracedServerStream{ServerStream: ss, ctx: ctx}
+		err = handler(srv, wrapped)
+		duration := time.Since(start)
+
+		grpcCode := grpcCodes.OK
+		if err != nil {
+			span.RecordError(err)
+			grpcCode = grpcStatus.Code(err)
+			span.SetAttributes(attribute.String("grpc.status", grpcCode.String()))
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+
+		metrics.ObserveGRPC(info.FullMethod, streamType(info), grpcCode, duration)
+
+		traceID := span.SpanContext().TraceID().String()
+		trailerMD := metadata.Pairs("x-trace-id", traceID)
+		ss.SetTrailer(trailerMD)
+
+		span.SetAttributes(
+			attribute.String("grpc.remote_addr", remoteAddr),
+			attribute.Int64("grpc.stream.sent", wrapped.sent),
+		)
+
+		respAttrs := logger.LogGRPCResponse(ctx, info.FullMethod, trailerMD, int32(grpcCode), nil, duration, "incoming::response")
+		respAttrs = append(respAttrs, slog.Int64("grpc.stream.sent", wrapped.sent))
+		logger.Info(ctx, "GRPC", respAttrs...)
+
+		return err
+	}
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return metrics.BidiStream
+	case info.IsClientStream:
+		return metrics.ClientStream
+	default:
+		return metrics.ServerStream
+	}
+}
+
 // Panic recovery (biar seragam sama HTTP middleware‑mu)
 func errFromRecover(rec interface{}) error {
 	if err, ok := rec.(error); ok {
@@ -0,0 +1,32 @@
+package middleware_http
+
+import (
+	"net/http"
+
+	"simple-crud/internal/auth"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// AuthMiddleware verifies the credentials of each request (a bearer JWT or
+// an X-API-Key) and attaches the principal, or why there is none, to the
+// request context. It rejects nothing itself: public routes must keep
+// working without credentials, so the routes decide (see
+// handler.RequireAuth). It belongs outermost, before TraceMiddleware, so the
+// request log carries the subject.
+func AuthMiddleware(authenticator *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization, apiKey := r.Header.Get("Authorization"), r.Header.Get("X-API-Key")
+			if authorization == "" && apiKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Join the caller's trace, so a key lookup is not a trace of its own.
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx = authenticator.Authenticate(ctx, authorization, apiKey)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware_http
+
+import (
+	"net/http"
+	"strings"
+
+	"simple-crud/internal/compression"
+)
+
+// CompressMiddleware compresses responses with the coding negotiated from
+// Accept-Encoding (zstd, br or gzip). Bodies shorter than minSize, already
+// compressed media types, partial content and responses that set their own
+// Content-Encoding are sent as is. It must wrap TraceMiddleware, so the
+// body-capturing ResponseWriter sees (and logs) the uncompressed body.
+func CompressMiddleware(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			coding := compression.Negotiate(r.Header.Get("Accept-Encoding"))
+			if coding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, coding: coding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter holds back the start of a response until it knows whether
+// to compress it: once minSize bytes are written, on Flush, or at the end.
+type compressWriter struct {
+	http.ResponseWriter
+	coding  string
+	minSize int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     compression.Writer
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	switch {
+	case code < 200:
+		// Informational responses go out at once; the final one follows.
+		w.ResponseWriter.WriteHeader(code)
+		return
+	case w.status != 0:
+		// Superfluous, as net/http would treat it.
+		return
+	}
+	w.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusPartialContent {
+		_ = w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.minSize {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush decides at once, treating the response as a stream worth
+// compressing, and pushes out what the encoder holds.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the status line and the held-back bytes, compressed if
+// compress is set and the response qualifies.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if compress && h.Get("Content-Encoding") == "" && h.Get("Content-Range") == "" &&
+		compression.Compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.coding)
+		h.Del("Content-Length")
+		// The compressed bytes differ, so a strong validator no longer
+		// identifies them.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		w.enc = compression.NewWriter(w.coding, w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends a response that ended before the decision, and finishes the
+// compressed stream.
+func (w *compressWriter) close() {
+	if !w.decided {
+		if w.status == 0 {
+			// Nothing was written; net/http sends the implicit 200.
+			return
+		}
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		compression.Release(w.coding, w.enc)
+		w.enc = nil
+	}
+}
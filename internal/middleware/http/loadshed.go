@@ -0,0 +1,60 @@
+package middleware_http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"simple-crud/internal/loadshed"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/problem"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoadShedMiddleware admits each request through the adaptive concurrency
+// limiter at the priority priorities gives its route pattern (unknown
+// routes are normal), and answers 503 with Retry-After at once when the
+// request is shed. A 503 or 504 from the handler counts as overload. It
+// belongs inside TraceMiddleware, whose span records the shedding, and
+// inside RateLimitMiddleware, so requests over their rate take no share of
+// the limit.
+func LoadShedMiddleware(limiter *loadshed.Limiter, routes RouteMatcher, priorities map[string]loadshed.Priority) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, route := routes.Handler(r)
+			priority, ok := priorities[route]
+			if !ok {
+				priority = loadshed.PriorityNormal
+			}
+
+			token, ok := limiter.Acquire(priority)
+			if !ok {
+				limit, inflight := limiter.State()
+				attrs := []attribute.KeyValue{
+					attribute.String("loadshed.priority", priority.String()),
+					attribute.Int("loadshed.limit", limit),
+					attribute.Int("loadshed.inflight", inflight),
+				}
+				span := trace.SpanFromContext(r.Context())
+				span.SetAttributes(attribute.Bool("loadshed.shed", true))
+				span.AddEvent("request shed", trace.WithAttributes(attrs...))
+				logger.Warn(r.Context(), "Request shed",
+					slog.String("data.route", route),
+					slog.String("data.priority", priority.String()),
+					slog.Int("data.limit", limit),
+					slog.Int("data.inflight", inflight),
+				)
+				w.Header().Set("Retry-After", "1")
+				problem.Error(w, r, "server is overloaded; retry shortly", http.StatusServiceUnavailable)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			defer func() {
+				token.Release(rw.statusCode == http.StatusServiceUnavailable || rw.statusCode == http.StatusGatewayTimeout)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware_http
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/loadshed"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/problem"
+	"simple-crud/internal/ratelimit"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// RateLimitMiddleware charges each request to its client's bucket for the
+// matched route pattern (e.g. "POST /products") and answers 429 with
+// Retry-After once the bucket is empty. Limited routes carry the
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers. If the store fails the request is let through. Routes priorities
+// marks critical (health checks and metrics), which load shedding never
+// sheds, are not limited either. It belongs inside AuthMiddleware, so
+// authenticated clients are keyed by principal rather than IP, and inside
+// TraceMiddleware, so rejections are logged.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, routes RouteMatcher, priorities map[string]loadshed.Priority) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, route := routes.Handler(r)
+			if priorities[route] == loadshed.PriorityCritical {
+				next.ServeHTTP(w, r)
+				return
+			}
+			client := ratelimit.ClientKey(r.Context(), remoteIP(r))
+			res, limit, limited, err := limiter.Allow(r.Context(), route, client)
+			if err != nil {
+				ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+				logger.Warn(ctx, "Rate limit store unavailable; allowing request",
+					slog.String("exception.message", err.Error()),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window())))
+			if res.Allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			retryAfter := max(ceilSeconds(res.RetryAfter), 1)
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			logger.Warn(ctx, "Rate limit exceeded",
+				slog.String("enduser.id", auth.Subject(r.Context())),
+				slog.String("data.route", route),
+				slog.String("data.client", client),
+				slog.Int64("data.retry_after_ms", res.RetryAfter.Milliseconds()),
+			)
+			h.Set("Retry-After", strconv.Itoa(retryAfter))
+			problem.Error(w, r, fmt.Sprintf("rate limit exceeded; retry in %ds", retryAfter), http.StatusTooManyRequests)
+		})
+	}
+}
+
+// remoteIP is the host part of the connection's remote address.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,73 @@
+package middleware_http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/problem"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// RecoverMiddleware turns a handler panic into a 500 problem response instead
+// of a dropped connection. It belongs inside TraceMiddleware, which then
+// records the 500 on the request span. If the handler already started the
+// response, the panic can only be logged.
+func RecoverMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &startedWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Deliberate abort; let net/http drop the connection.
+					panic(rec)
+				}
+
+				ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+				err := errFromRecover(rec)
+				logger.Error(ctx, "Recovered from panic",
+					slog.String("exception.message", fmt.Sprint(rec)),
+					slog.String("exception.type", fmt.Sprintf("%T", err)),
+					slog.String("exception.stacktrace", string(debug.Stack())),
+				)
+				if rw.started {
+					return
+				}
+				p := problem.New(http.StatusInternalServerError, "")
+				p.Type = problem.TypeInternal
+				problem.Write(w, r, p)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// startedWriter records whether the response status line was sent.
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) WriteHeader(code int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *startedWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *startedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
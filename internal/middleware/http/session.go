@@ -0,0 +1,21 @@
+package middleware_http
+
+import (
+	"net/http"
+
+	"simple-crud/internal/database"
+)
+
+// MongoSessionMiddleware attaches a causally consistent MongoDB session to each
+// request context, so a GetByID issued after a write in the same request keeps
+// read-your-writes even when reads are routed to secondaries.
+func MongoSessionMiddleware(db *database.Mongo) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, end := db.WithCausalSession(r.Context())
+			defer end()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
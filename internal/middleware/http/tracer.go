@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"simple-crud/internal/logger"
+	"simple-crud/internal/metrics"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 var tracer = otel.Tracer("HttpMiddleware")
@@ -47,17 +50,40 @@ func (rw *ResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streamed responses can be flushed.
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// RouteMatcher resolves the pattern a request will be routed to;
+// *http.ServeMux implements it.
+type RouteMatcher interface {
+	Handler(r *http.Request) (h http.Handler, pattern string)
+}
+
 // TraceMiddleware wraps HTTP handlers with OpenTelemetry tracing.
 // It captures request & response metadata, injects trace ID into response headers,
 // handles panics safely, and logs enriched request data.
-func TraceMiddleware(globalCtx context.Context) func(http.Handler) http.Handler {
+// Spans are named after the matched route pattern (e.g. "GET /products/{id}")
+// rather than the raw path, keeping span names low-cardinality; the request
+// metrics are labelled the same way.
+func TraceMiddleware(globalCtx context.Context, routes RouteMatcher) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Extract trace context from incoming request headers
 			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 
 			// Start span with the extracted context (will continue existing trace if present)
-			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path)
+			route := routeOf(routes, r)
+			spanName := r.Method
+			if route != "" {
+				spanName += " " + route
+			}
+			ctx, span := tracer.Start(ctx, spanName)
+			if route != "" {
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
 			defer func() {
 				if rec := recover(); rec != nil {
 					span.RecordError(errFromRecover(rec))
@@ -81,8 +107,8 @@ func TraceMiddleware(globalCtx context.Context) func(http.Handler) http.Handler
 			rw.Header().Set("X-Trace-ID", traceID)
 			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
 
-			// Call actual handler
-			next.ServeHTTP(rw, r)
+			// Call actual handler; inner middleware can annotate the span
+			next.ServeHTTP(rw, r.WithContext(ctx))
 
 			// Set OpenTelemetry span status
 			if rw.statusCode >= 500 {
@@ -94,6 +120,7 @@ func TraceMiddleware(globalCtx context.Context) func(http.Handler) http.Handler
 			}
 
 			duration := time.Since(start)
+			metrics.ObserveHTTP(r.Method, route, rw.statusCode, duration)
 
 			attrs = logger.LogHTTPResponse(ctx, r, rw.Header(), rw.statusCode, &rw.buf, duration.Milliseconds(), "incoming::response")
 			logger.Info(ctx, "HTTP", attrs...)
@@ -101,6 +128,16 @@ func TraceMiddleware(globalCtx context.Context) func(http.Handler) http.Handler
 	}
 }
 
+// routeOf returns the path template of the route r matches, without the
+// method prefix, or "" when nothing matches.
+func routeOf(routes RouteMatcher, r *http.Request) string {
+	_, pattern := routes.Handler(r)
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+	return pattern
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code and response size.
 type responseWriter struct {
 	http.ResponseWriter
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey is a service-to-service credential. Only a SHA-256 hash of the key
+// is stored; the key itself is returned once, on create and rotate. Prefix
+// identifies the key in lists and logs and stays the same across rotations.
+type APIKey struct {
+	ID     primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name   string             `json:"name" bson:"name"`
+	Prefix string             `json:"prefix" bson:"prefix"`
+	Hash   string             `json:"-" bson:"hash"`
+	// PreviousHash keeps the key replaced by a rotation valid until
+	// PreviousExpiresAt, so callers can switch over without downtime.
+	PreviousHash      string     `json:"-" bson:"previous_hash,omitempty"`
+	PreviousExpiresAt *time.Time `json:"-" bson:"previous_expires_at,omitempty"`
+	Scopes            []string   `json:"scopes" bson:"scopes"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RotatedAt         *time.Time `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" bson:"created_at"`
+
+	// Key is the plaintext key, set only on the create/rotate response.
+	Key string `json:"key,omitempty" bson:"-"`
+}
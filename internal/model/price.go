@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schedule states. A schedule becomes active once its price is applied and
+// completed once the previous price is restored, or straight away when it
+// has no end.
+const (
+	ScheduleScheduled = "scheduled"
+	ScheduleActive    = "active"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+)
+
+// PriceSchedule sets Price from StartsAt and, when EndsAt is set, restores
+// the price the product had before.
+type PriceSchedule struct {
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ProductID     primitive.ObjectID `json:"product_id" bson:"product_id"`
+	Price         float64            `json:"price" bson:"price"`
+	StartsAt      time.Time          `json:"starts_at" bson:"starts_at"`
+	EndsAt        *time.Time         `json:"ends_at,omitempty" bson:"ends_at,omitempty"`
+	Status        string             `json:"status" bson:"status"`
+	PreviousPrice *float64           `json:"previous_price,omitempty" bson:"previous_price,omitempty"`
+	LockedUntil   time.Time          `json:"-" bson:"locked_until"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	AppliedAt     *time.Time         `json:"applied_at,omitempty" bson:"applied_at,omitempty"`
+	CompletedAt   *time.Time         `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// Price history sources.
+const (
+	PriceSourceManual   = "manual"
+	PriceSourceSchedule = "schedule"
+	PriceSourceRevert   = "revert"
+)
+
+// PriceChange records a price that became effective at EffectiveFrom.
+// EffectiveTo is not stored; it is filled from the next change when history
+// is read, and stays nil for the current price.
+type PriceChange struct {
+	ID            primitive.ObjectID  `json:"id,omitempty" bson:"_id,omitempty"`
+	ProductID     primitive.ObjectID  `json:"product_id" bson:"product_id"`
+	Price         float64             `json:"price" bson:"price"`
+	EffectiveFrom time.Time           `json:"effective_from" bson:"effective_from"`
+	EffectiveTo   *time.Time          `json:"effective_to,omitempty" bson:"-"`
+	Source        string              `json:"source" bson:"source"`
+	ScheduleID    *primitive.ObjectID `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"`
+}
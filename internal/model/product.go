@@ -1,10 +1,36 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Product struct {
-	ID    primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Name  string             `json:"name" bson:"name"`
-	Price float64            `json:"price" bson:"price"`
-	Stock int                `json:"stock" bson:"stock"`
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Price       float64            `json:"price" bson:"price"`
+	Stock       int                `json:"stock" bson:"stock"`
+	Category    string             `json:"category,omitempty" bson:"category,omitempty"`
+	Tags        []string           `json:"tags,omitempty" bson:"tags,omitempty"`
+	Attachments []Attachment       `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	// UpdatedAt is when the product last changed; zero for products
+	// written before it was tracked.
+	UpdatedAt time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// ChangeMarker tracks writes to a whole collection: Version goes up by one
+// on every change, so it validates a listing without reading it.
+type ChangeMarker struct {
+	Version   int64     `json:"version" bson:"version"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Attachment describes a file stored in the blob store under ID.
+type Attachment struct {
+	ID          string    `json:"id" bson:"id"`
+	Filename    string    `json:"filename" bson:"filename"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	UploadedAt  time.Time `json:"uploaded_at" bson:"uploaded_at"`
 }
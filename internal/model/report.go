@@ -0,0 +1,22 @@
+package model
+
+type InventoryValue struct {
+	TotalValue   float64 `json:"total_value" bson:"total_value"`
+	TotalStock   int64   `json:"total_stock" bson:"total_stock"`
+	ProductCount int64   `json:"product_count" bson:"product_count"`
+}
+
+// PriceBucket is one histogram bucket covering [Min, Max). A nil bound means
+// the bucket is open on that side.
+type PriceBucket struct {
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+type GroupCount struct {
+	Key   string  `json:"key" bson:"_id"`
+	Count int64   `json:"count" bson:"count"`
+	Stock int64   `json:"stock" bson:"stock"`
+	Value float64 `json:"value" bson:"value"`
+}
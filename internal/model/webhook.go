@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Product change events delivered to webhook subscribers.
+const (
+	EventProductCreated = "product.created"
+	EventProductUpdated = "product.updated"
+	EventProductDeleted = "product.deleted"
+)
+
+// WebhookEvents lists every event a subscription may ask for.
+var WebhookEvents = []string{EventProductCreated, EventProductUpdated, EventProductDeleted}
+
+type WebhookSubscription struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	URL       string             `json:"url" bson:"url"`
+	Events    []string           `json:"events" bson:"events"`
+	Secret    string             `json:"secret,omitempty" bson:"secret"`
+	Disabled  bool               `json:"disabled" bson:"disabled"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// Delivery states. Dead deliveries exhausted their retries and are kept as a
+// dead-letter record until retried manually.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	SubscriptionID primitive.ObjectID `json:"subscription_id" bson:"subscription_id"`
+	Event          string             `json:"event" bson:"event"`
+	Payload        string             `json:"payload" bson:"payload"`
+	Status         string             `json:"status" bson:"status"`
+	Attempts       int                `json:"attempts" bson:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at" bson:"next_attempt_at"`
+	LockedUntil    time.Time          `json:"-" bson:"locked_until"`
+	LastError      string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	// TraceContext carries the W3C trace headers of the request that
+	// produced the event, so the delivery joins the same trace.
+	TraceContext map[string]string `json:"-" bson:"trace_context,omitempty"`
+	History      []DeliveryAttempt `json:"history,omitempty" bson:"history,omitempty"`
+	CreatedAt    time.Time         `json:"created_at" bson:"created_at"`
+	DeliveredAt  *time.Time        `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
+
+type DeliveryAttempt struct {
+	At         time.Time `json:"at" bson:"at"`
+	StatusCode int       `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms" bson:"duration_ms"`
+}
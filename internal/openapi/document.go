@@ -0,0 +1,162 @@
+// Package openapi builds OpenAPI 3.1 documents. Schemas are derived from Go
+// types by reflection, or from protobuf descriptors for messages encoded with
+// protojson, so the JSON a handler encodes and the schema that describes it
+// come from the same definitions.
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema is the subset of JSON Schema 2020-12 the API needs. Type is a
+// string, or a list when the value may also be null.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	ContentMediaType     string             `json:"contentMediaType,omitempty"`
+}
+
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      map[string]*PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+}
+
+// Add documents method + path. Path uses ServeMux pattern syntax; "/{$}" is
+// written as "/". Adding the same operation twice panics, like registering
+// a pattern twice on a ServeMux.
+func (d *Document) Add(method, path string, op *Operation) {
+	path = specPath(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	m := strings.ToLower(method)
+	if _, dup := (*item)[m]; dup {
+		panic(fmt.Sprintf("openapi: %s %s documented twice", method, path))
+	}
+	(*item)[m] = op
+}
+
+// Operations lists the documented operations as "METHOD /path", sorted.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method := range *item {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// CheckRoutes compares the document with the ServeMux patterns actually
+// registered ("METHOD /path") and reports every route that is missing from
+// the document and every operation that has no route.
+func (d *Document) CheckRoutes(patterns []string) error {
+	registered := map[string]bool{}
+	for _, p := range patterns {
+		method, path, _ := strings.Cut(p, " ")
+		registered[strings.ToUpper(method)+" "+specPath(path)] = true
+	}
+	documented := map[string]bool{}
+	for _, op := range d.Operations() {
+		documented[op] = true
+	}
+
+	var problems []string
+	for p := range registered {
+		if !documented[p] {
+			problems = append(problems, "undocumented route "+p)
+		}
+	}
+	for p := range documented {
+		if !registered[p] {
+			problems = append(problems, "documented operation without a route "+p)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("openapi spec and routes drifted: %s", strings.Join(problems, "; "))
+}
+
+func specPath(path string) string {
+	return strings.TrimSuffix(path, "{$}")
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SchemaOfMessage returns the schema of m's protojson encoding, as the HTTP
+// gateway writes it: proto field names, every field emitted except unset
+// optional scalars (unset messages as null), 64-bit integers as strings and
+// Timestamps as RFC 3339 strings. Messages are registered under their full
+// proto name, e.g. "product.Product", so they cannot collide with Go model
+// schemas.
+func (d *Document) SchemaOfMessage(m proto.Message) *Schema {
+	return d.messageSchema(m.ProtoReflect().Descriptor())
+}
+
+// ArrayOfMessage returns an array schema of m's type.
+func (d *Document) ArrayOfMessage(m proto.Message) *Schema {
+	return &Schema{Type: "array", Items: d.SchemaOfMessage(m)}
+}
+
+func (d *Document) messageSchema(md protoreflect.MessageDescriptor) *Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &Schema{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?s$`}
+	case "google.protobuf.Empty":
+		return &Schema{Type: "object"}
+	}
+
+	name := string(md.FullName())
+	if _, ok := d.Components.Schemas[name]; !ok {
+		// Reserve the name first so recursive messages terminate.
+		d.Components.Schemas[name] = &Schema{}
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Get(i)
+			s.Properties[string(f.Name())] = d.fieldSchema(f)
+		}
+		*d.Components.Schemas[name] = *s
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (d *Document) fieldSchema(f protoreflect.FieldDescriptor) *Schema {
+	switch {
+	case f.IsMap():
+		return &Schema{Type: "object", AdditionalProperties: d.singularSchema(f.MapValue())}
+	case f.IsList():
+		return &Schema{Type: "array", Items: d.singularSchema(f)}
+	}
+	s := d.singularSchema(f)
+	// Unset message fields are written as null; refs cannot carry the null
+	// alternative, so only inline schemas such as Timestamp get it.
+	if f.Kind() == protoreflect.MessageKind && s.Ref == "" {
+		if typ, ok := s.Type.(string); ok {
+			s.Type = []string{typ, "null"}
+		}
+	}
+	return s
+}
+
+func (d *Document) singularSchema(f protoreflect.FieldDescriptor) *Schema {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return d.messageSchema(f.Message())
+	case protoreflect.EnumKind:
+		values := f.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return &Schema{Type: "string", Enum: names}
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson writes 64-bit integers as strings to keep precision.
+		return &Schema{Type: "string", Format: "int64", Pattern: "^-?[0-9]+$"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
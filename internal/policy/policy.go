@@ -0,0 +1,285 @@
+// Package policy decides what an authenticated caller may do with products.
+// A policy is a set of roles, each a list of allow and deny rules over
+// actions, optionally narrowed to product fields and categories; callers get
+// roles from their token, their subject or their credential's scopes.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"simple-crud/internal/auth"
+)
+
+// Actions on products.
+const (
+	ActionProductCreate = "product:create"
+	ActionProductRead   = "product:read"
+	ActionProductUpdate = "product:update"
+	ActionProductDelete = "product:delete"
+)
+
+var knownActions = []string{ActionProductCreate, ActionProductRead, ActionProductUpdate, ActionProductDelete}
+
+// Fields a rule may be narrowed to: the updatable product fields, plus the
+// attachments, which are changed through their own routes.
+var knownFields = []string{"name", "price", "stock", "category", "tags", "attachments"}
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// ErrInvalidPolicy wraps every reason a policy document was rejected.
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// Policy is the parsed policy document.
+type Policy struct {
+	// Roles by name.
+	Roles map[string]Role `json:"roles"`
+	// SubjectRoles grants roles to principals by subject.
+	SubjectRoles map[string][]string `json:"subject_roles"`
+	// ScopeRoles grants roles to principals holding a scope; this is how
+	// API keys, which carry no roles, get any.
+	ScopeRoles map[string][]string `json:"scope_roles"`
+	// AnonymousRoles apply to requests without a principal, i.e. when
+	// authentication is off or for in-process callers.
+	AnonymousRoles []string `json:"anonymous_roles"`
+}
+
+// Role is a named set of rules, plus those of the roles it inherits.
+type Role struct {
+	Inherits []string `json:"inherits"`
+	Rules    []Rule   `json:"rules"`
+}
+
+// Rule allows or denies Actions. An empty Fields matches every field; an
+// empty Categories matches every product. Rules with Categories never match
+// list reads, which have no single product to look at.
+type Rule struct {
+	// Name identifies the rule in audit logs; it defaults to "<role>#<n>".
+	Name       string   `json:"name"`
+	Effect     string   `json:"effect"`
+	Actions    []string `json:"actions"`
+	Fields     []string `json:"fields"`
+	Categories []string `json:"categories"`
+}
+
+// Request is one authorization question.
+type Request struct {
+	Roles  []string
+	Action string
+	// Fields are the fields an update changes; empty for other actions.
+	Fields []string
+	// Resource is the product acted on (for creates, the one proposed); nil
+	// for list reads.
+	Resource *Resource
+}
+
+// Resource holds the product attributes rules can match on.
+type Resource struct {
+	ID       string
+	Category string
+}
+
+// Decision is the outcome of a Request.
+type Decision struct {
+	Allowed bool
+	// Roles are the roles evaluated, inherited ones included.
+	Roles []string
+	// Rule names the deciding deny rule, or the allow rules that granted
+	// the request; empty when nothing matched (default deny).
+	Rule string
+	// Fields lists the requested fields that were denied.
+	Fields []string
+	// Reason explains a denial without naming roles or rules, so it can be
+	// returned to the caller.
+	Reason string
+}
+
+// Parse decodes and validates a JSON policy document.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) validate() error {
+	var errs []error
+	for name, role := range p.Roles {
+		for _, parent := range role.Inherits {
+			if _, ok := p.Roles[parent]; !ok {
+				errs = append(errs, fmt.Errorf("role %q inherits unknown role %q", name, parent))
+			}
+		}
+		for i := range role.Rules {
+			r := &role.Rules[i]
+			if r.Name == "" {
+				r.Name = fmt.Sprintf("%s#%d", name, i+1)
+			}
+			if r.Effect != EffectAllow && r.Effect != EffectDeny {
+				errs = append(errs, fmt.Errorf("rule %s: effect must be %q or %q", r.Name, EffectAllow, EffectDeny))
+			}
+			if len(r.Actions) == 0 {
+				errs = append(errs, fmt.Errorf("rule %s: actions are required", r.Name))
+			}
+			for _, a := range r.Actions {
+				if a != "*" && a != "product:*" && !slices.Contains(knownActions, a) {
+					errs = append(errs, fmt.Errorf("rule %s: unknown action %q", r.Name, a))
+				}
+			}
+			for _, f := range r.Fields {
+				if !slices.Contains(knownFields, f) {
+					errs = append(errs, fmt.Errorf("rule %s: unknown field %q", r.Name, f))
+				}
+			}
+		}
+	}
+	grants := map[string][]string{"anonymous_roles": p.AnonymousRoles}
+	for subject, roles := range p.SubjectRoles {
+		grants["subject_roles."+subject] = roles
+	}
+	for scope, roles := range p.ScopeRoles {
+		grants["scope_roles."+scope] = roles
+	}
+	for where, roles := range grants {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown role %q", where, role))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RolesFor returns the roles p grants to principal, nil meaning anonymous.
+// Roles the policy does not define are dropped.
+func (p *Policy) RolesFor(principal *auth.Principal) []string {
+	if principal == nil {
+		return slices.Clone(p.AnonymousRoles)
+	}
+	var roles []string
+	add := func(names ...string) {
+		for _, name := range names {
+			if _, ok := p.Roles[name]; ok && !slices.Contains(roles, name) {
+				roles = append(roles, name)
+			}
+		}
+	}
+	add(principal.Roles...)
+	add(p.SubjectRoles[principal.Subject]...)
+	for _, scope := range principal.Scopes {
+		add(p.ScopeRoles[scope]...)
+	}
+	return roles
+}
+
+// Evaluate answers req. Deny rules win over allow rules, and anything not
+// allowed is denied. For an update every changed field must be allowed by
+// some rule and denied by none.
+func (p *Policy) Evaluate(req Request) Decision {
+	d := Decision{Roles: p.expand(req.Roles)}
+
+	var allows []Rule
+	for _, name := range d.Roles {
+		for _, r := range p.Roles[name].Rules {
+			if !r.matches(req) {
+				continue
+			}
+			if r.Effect == EffectAllow {
+				allows = append(allows, r)
+				continue
+			}
+			if len(r.Fields) == 0 {
+				d.Rule = r.Name
+				d.Reason = req.Action + " is not permitted"
+				return d
+			}
+			if denied := intersect(req.Fields, r.Fields); len(denied) > 0 {
+				d.Rule = r.Name
+				d.Fields = denied
+				d.Reason = req.Action + " of " + strings.Join(denied, ", ") + " is not permitted"
+				return d
+			}
+		}
+	}
+	if len(allows) == 0 {
+		d.Reason = req.Action + " is not permitted"
+		return d
+	}
+
+	for _, f := range req.Fields {
+		covered := false
+		for _, r := range allows {
+			if len(r.Fields) == 0 || slices.Contains(r.Fields, f) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			d.Fields = append(d.Fields, f)
+		}
+	}
+	if len(d.Fields) > 0 {
+		d.Reason = req.Action + " of " + strings.Join(d.Fields, ", ") + " is not permitted"
+		return d
+	}
+	names := make([]string, len(allows))
+	for i, r := range allows {
+		names[i] = r.Name
+	}
+	d.Allowed = true
+	d.Rule = strings.Join(names, ",")
+	return d
+}
+
+// expand adds inherited roles, breadth first and without repeats, so a
+// cycle in the document cannot loop.
+func (p *Policy) expand(roles []string) []string {
+	var out []string
+	queue := slices.Clone(roles)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		role, ok := p.Roles[name]
+		if !ok || slices.Contains(out, name) {
+			continue
+		}
+		out = append(out, name)
+		queue = append(queue, role.Inherits...)
+	}
+	return out
+}
+
+func (r Rule) matches(req Request) bool {
+	if !slices.ContainsFunc(r.Actions, func(a string) bool {
+		return a == "*" || a == req.Action || (strings.HasSuffix(a, ":*") && strings.HasPrefix(req.Action, strings.TrimSuffix(a, "*")))
+	}) {
+		return false
+	}
+	if len(r.Categories) == 0 {
+		return true
+	}
+	return req.Resource != nil && slices.Contains(r.Categories, req.Resource.Category)
+}
+
+func intersect(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,232 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+const testPolicy = `{
+  "roles": {
+    "reader": {
+      "rules": [{"name": "read", "effect": "allow", "actions": ["product:read"]}]
+    },
+    "pricer": {
+      "inherits": ["reader"],
+      "rules": [
+        {"name": "edit-price", "effect": "allow", "actions": ["product:update"], "fields": ["price"]},
+        {"name": "no-delete", "effect": "deny", "actions": ["product:delete"]}
+      ]
+    },
+    "stocker": {
+      "rules": [{"name": "edit-stock", "effect": "allow", "actions": ["product:update"], "fields": ["stock"]}]
+    },
+    "syrups": {
+      "rules": [{"name": "manage-syrups", "effect": "allow", "actions": ["product:*"], "categories": ["syrup"]}]
+    },
+    "admin": {
+      "rules": [{"name": "all", "effect": "allow", "actions": ["*"]}]
+    },
+    "no-rename": {
+      "rules": [{"name": "keep-names", "effect": "deny", "actions": ["product:update"], "fields": ["name"]}]
+    },
+    "ping": {"inherits": ["pong"], "rules": [{"name": "ping-read", "effect": "allow", "actions": ["product:read"]}]},
+    "pong": {"inherits": ["ping"], "rules": [{"name": "pong-create", "effect": "allow", "actions": ["product:create"]}]}
+  }
+}`
+
+func mustParse(t *testing.T, doc string) *Policy {
+	t.Helper()
+	p, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	p := mustParse(t, testPolicy)
+	syrup := &Resource{ID: "1", Category: "syrup"}
+	juice := &Resource{ID: "2", Category: "juice"}
+
+	tests := []struct {
+		name         string
+		req          Request
+		allowed      bool
+		rule         string
+		deniedFields []string
+	}{
+		{
+			name: "no roles is denied",
+			req:  Request{Action: ActionProductRead, Resource: juice},
+		},
+		{
+			name:    "allow rule grants",
+			req:     Request{Roles: []string{"reader"}, Action: ActionProductRead, Resource: juice},
+			allowed: true,
+			rule:    "read",
+		},
+		{
+			name: "unmatched action is denied",
+			req:  Request{Roles: []string{"reader"}, Action: ActionProductCreate, Resource: juice},
+		},
+		{
+			name:    "inherited rule grants",
+			req:     Request{Roles: []string{"pricer"}, Action: ActionProductRead, Resource: juice},
+			allowed: true,
+			rule:    "read",
+		},
+		{
+			name:    "update of an allowed field",
+			req:     Request{Roles: []string{"pricer"}, Action: ActionProductUpdate, Fields: []string{"price"}, Resource: juice},
+			allowed: true,
+			rule:    "edit-price",
+		},
+		{
+			name:         "every changed field must be allowed",
+			req:          Request{Roles: []string{"pricer"}, Action: ActionProductUpdate, Fields: []string{"price", "stock"}, Resource: juice},
+			deniedFields: []string{"stock"},
+		},
+		{
+			name:    "fields may be covered by different roles",
+			req:     Request{Roles: []string{"pricer", "stocker"}, Action: ActionProductUpdate, Fields: []string{"price", "stock"}, Resource: juice},
+			allowed: true,
+			rule:    "edit-price,edit-stock",
+		},
+		{
+			name: "deny wins over allow",
+			req:  Request{Roles: []string{"pricer", "admin"}, Action: ActionProductDelete, Resource: juice},
+			rule: "no-delete",
+		},
+		{
+			name:         "field deny wins over allow",
+			req:          Request{Roles: []string{"admin", "no-rename"}, Action: ActionProductUpdate, Fields: []string{"price", "name"}, Resource: juice},
+			rule:         "keep-names",
+			deniedFields: []string{"name"},
+		},
+		{
+			name:    "field deny ignores other fields",
+			req:     Request{Roles: []string{"admin", "no-rename"}, Action: ActionProductUpdate, Fields: []string{"price"}, Resource: juice},
+			allowed: true,
+			rule:    "all",
+		},
+		{
+			name:    "category rule matches its category",
+			req:     Request{Roles: []string{"syrups"}, Action: ActionProductDelete, Resource: syrup},
+			allowed: true,
+			rule:    "manage-syrups",
+		},
+		{
+			name: "category rule skips other categories",
+			req:  Request{Roles: []string{"syrups"}, Action: ActionProductDelete, Resource: juice},
+		},
+		{
+			name: "category rule skips list reads",
+			req:  Request{Roles: []string{"syrups"}, Action: ActionProductRead},
+		},
+		{
+			name:    "inheritance cycle terminates",
+			req:     Request{Roles: []string{"ping"}, Action: ActionProductCreate, Resource: juice},
+			allowed: true,
+			rule:    "pong-create",
+		},
+		{
+			name:    "unknown roles are ignored",
+			req:     Request{Roles: []string{"ghost", "reader"}, Action: ActionProductRead},
+			allowed: true,
+			rule:    "read",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := p.Evaluate(tt.req)
+			if d.Allowed != tt.allowed {
+				t.Fatalf("Allowed = %v, want %v (reason %q)", d.Allowed, tt.allowed, d.Reason)
+			}
+			if d.Rule != tt.rule {
+				t.Errorf("Rule = %q, want %q", d.Rule, tt.rule)
+			}
+			if !slices.Equal(d.Fields, tt.deniedFields) {
+				t.Errorf("Fields = %v, want %v", d.Fields, tt.deniedFields)
+			}
+			if !d.Allowed && d.Reason == "" {
+				t.Error("denial has no reason")
+			}
+		})
+	}
+}
+
+func TestExpandCycle(t *testing.T) {
+	p := mustParse(t, testPolicy)
+	got := p.expand([]string{"ping", "ping"})
+	if want := []string{"ping", "pong"}; !slices.Equal(got, want) {
+		t.Fatalf("expand = %v, want %v", got, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"malformed", `{"roles":`},
+		{"unknown key", `{"rolez": {}}`},
+		{"unknown parent", `{"roles": {"a": {"inherits": ["b"]}}}`},
+		{"bad effect", `{"roles": {"a": {"rules": [{"effect": "maybe", "actions": ["product:read"]}]}}}`},
+		{"no actions", `{"roles": {"a": {"rules": [{"effect": "allow"}]}}}`},
+		{"unknown action", `{"roles": {"a": {"rules": [{"effect": "allow", "actions": ["product:fly"]}]}}}`},
+		{"unknown field", `{"roles": {"a": {"rules": [{"effect": "allow", "actions": ["product:update"], "fields": ["colour"]}]}}}`},
+		{"unknown granted role", `{"roles": {}, "scope_roles": {"admin": ["root"]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.doc)); !errors.Is(err, ErrInvalidPolicy) {
+				t.Fatalf("Parse error = %v, want ErrInvalidPolicy", err)
+			}
+		})
+	}
+}
+
+func TestParseNamesRules(t *testing.T) {
+	p := mustParse(t, `{"roles": {"a": {"rules": [{"effect": "allow", "actions": ["product:read"]}]}}}`)
+	if got := p.Roles["a"].Rules[0].Name; got != "a#1" {
+		t.Fatalf("rule name = %q, want %q", got, "a#1")
+	}
+}
+
+func TestExamplePolicy(t *testing.T) {
+	data, err := os.ReadFile("../../policy.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := mustParse(t, string(data))
+	syrup := &Resource{ID: "1", Category: "syrup"}
+	juice := &Resource{ID: "2", Category: "juice"}
+
+	tests := []struct {
+		name    string
+		roles   []string
+		req     Request
+		allowed bool
+	}{
+		{"viewer reads", []string{"viewer"}, Request{Action: ActionProductRead}, true},
+		{"viewer cannot update", []string{"viewer"}, Request{Action: ActionProductUpdate, Fields: []string{"price"}, Resource: juice}, false},
+		{"editor reads through viewer", []string{"editor"}, Request{Action: ActionProductRead, Resource: juice}, true},
+		{"editor updates price and stock", []string{"editor"}, Request{Action: ActionProductUpdate, Fields: []string{"price", "stock"}, Resource: juice}, true},
+		{"editor cannot rename", []string{"editor"}, Request{Action: ActionProductUpdate, Fields: []string{"name"}, Resource: juice}, false},
+		{"editor cannot delete", []string{"editor"}, Request{Action: ActionProductDelete, Resource: juice}, false},
+		{"syrup manager renames syrups", []string{"syrup-manager"}, Request{Action: ActionProductUpdate, Fields: []string{"name"}, Resource: syrup}, true},
+		{"syrup manager cannot rename juice", []string{"syrup-manager"}, Request{Action: ActionProductUpdate, Fields: []string{"name"}, Resource: juice}, false},
+		{"syrup manager cannot delete syrups", []string{"syrup-manager"}, Request{Action: ActionProductDelete, Resource: syrup}, false},
+		{"catalog admin deletes", []string{"catalog-admin"}, Request{Action: ActionProductDelete, Resource: juice}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Roles = tt.roles
+			if d := p.Evaluate(tt.req); d.Allowed != tt.allowed {
+				t.Fatalf("Allowed = %v, want %v (rule %q, reason %q)", d.Allowed, tt.allowed, d.Rule, d.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/logger"
+)
+
+// Store holds the policy loaded from a file and swaps in a new one when the
+// file changes. A document that fails to load leaves the previous policy in
+// force.
+type Store struct {
+	path    string
+	current atomic.Pointer[loaded]
+}
+
+type loaded struct {
+	policy  *Policy
+	digest  string
+	modTime time.Time
+	size    int64
+}
+
+// NewStore loads the policy at path. Unlike a reload, a bad document here is
+// an error: there is no previous policy to fall back to.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	l, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(l)
+	return s, nil
+}
+
+func (s *Store) load() (*loaded, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &loaded{
+		policy:  p,
+		digest:  hex.EncodeToString(sum[:6]),
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}, nil
+}
+
+// Digest identifies the policy in force; audit logs carry it.
+func (s *Store) Digest() string {
+	return s.current.Load().digest
+}
+
+// Authorize evaluates req for the principal in ctx, filling in its roles.
+func (s *Store) Authorize(ctx context.Context, req Request) Decision {
+	p := s.current.Load().policy
+	principal, _ := auth.FromContext(ctx)
+	req.Roles = p.RolesFor(principal)
+	return p.Evaluate(req)
+}
+
+// Watch reloads the policy whenever the file's modification time or size
+// changes, checking every interval until ctx is cancelled.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	logger.Info(ctx, "Policy watcher started",
+		slog.String("data.path", s.path),
+		slog.String("data.digest", s.Digest()),
+		slog.Int64("data.interval_ms", interval.Milliseconds()),
+	)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Policy watcher shutting down")
+			return
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+func (s *Store) reload(ctx context.Context) {
+	prev := s.current.Load()
+	info, err := os.Stat(s.path)
+	if err == nil && info.ModTime().Equal(prev.modTime) && info.Size() == prev.size {
+		return
+	}
+	l, err := s.load()
+	if err != nil {
+		logger.Error(ctx, "Failed to reload policy; keeping the previous one",
+			slog.String("data.path", s.path),
+			slog.String("data.digest", prev.digest),
+			slog.String("exception.message", err.Error()),
+		)
+		// Do not retry the same broken file every tick.
+		if info != nil {
+			s.current.Store(&loaded{policy: prev.policy, digest: prev.digest, modTime: info.ModTime(), size: info.Size()})
+		}
+		return
+	}
+	s.current.Store(l)
+	if l.digest != prev.digest {
+		logger.Info(ctx, "Policy reloaded",
+			slog.String("data.path", s.path),
+			slog.String("data.previous_digest", prev.digest),
+			slog.String("data.digest", l.digest),
+		)
+	}
+}
@@ -0,0 +1,96 @@
+// Package problem writes RFC 9457 (formerly RFC 7807) problem details, the
+// application/problem+json error body of the HTTP API.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem types. TypeBlank means the status code says it all; the others
+// name problems clients are expected to handle specifically.
+const (
+	TypeBlank      = "about:blank"
+	TypeValidation = "/problems/validation-error"
+	TypeInternal   = "/problems/internal-error"
+)
+
+// Problem is one problem details object. Errors is an extension member
+// listing the fields that failed validation.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is one failed validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns an about:blank problem for status, titled with the status text.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   TypeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Validation returns a 400 problem listing the invalid fields.
+func Validation(detail string, fields []FieldError) *Problem {
+	return &Problem{
+		Type:   TypeValidation,
+		Title:  "Request validation failed",
+		Status: http.StatusBadRequest,
+		Detail: detail,
+		Errors: fields,
+	}
+}
+
+// Write sends p for request r, filling in the instance (the request path)
+// and the ID of the trace the request belongs to.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" {
+		p.Instance = r.URL.RequestURI()
+	}
+	if p.TraceID == "" {
+		p.TraceID = TraceID(r)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Error is Write for a plain status and message, the problem+json
+// counterpart of http.Error.
+func Error(w http.ResponseWriter, r *http.Request, detail string, status int) {
+	Write(w, r, New(status, detail))
+}
+
+// TraceID returns the trace the request is part of: the span in its context,
+// or else the trace context the tracing middleware propagated in its headers.
+func TraceID(r *http.Request) string {
+	sc := trace.SpanContextFromContext(r.Context())
+	if !sc.IsValid() {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		sc = trace.SpanContextFromContext(ctx)
+	}
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
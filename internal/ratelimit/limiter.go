@@ -0,0 +1,197 @@
+// Package ratelimit throttles clients with token buckets: each client gets a
+// bucket per rate-limited route (or one shared bucket for the routes without
+// a limit of their own) that refills at a steady rate up to a burst size.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+)
+
+// Limit is a refill rate in tokens per second and the bucket size. The zero
+// Limit means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether l imposes no limit.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Window is how long an empty bucket takes to refill.
+func (l Limit) Window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Result is the state of a bucket after a request took (or failed to take)
+// a token from it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available; zero if allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps buckets. Take refills the bucket for key according to limit,
+// then takes one token if there is one.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// NewStore builds the store selected by cfg.RateLimitStore: "memory" limits
+// each replica on its own, "mongo" shares buckets across replicas.
+func NewStore(ctx context.Context, cfg *config.Config, db *database.Mongo) (Store, error) {
+	switch cfg.RateLimitStore {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "mongo":
+		return NewMongoStore(ctx, db, "rate_limit")
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+}
+
+// result derives a Result from the tokens left in a bucket.
+func result(allowed bool, tokens float64, limit Limit) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second)),
+	}
+	if !allowed {
+		r.RetryAfter = time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	}
+	return r
+}
+
+// Limiter picks the limit for a route and charges the client's bucket.
+type Limiter struct {
+	store    Store
+	fallback Limit
+	routes   map[string]Limit
+}
+
+// NewLimiter limits routes in routes by their own limit and every other
+// route by fallback, which may be unlimited.
+func NewLimiter(store Store, fallback Limit, routes map[string]Limit) *Limiter {
+	return &Limiter{store: store, fallback: fallback, routes: routes}
+}
+
+// NewLimiterFromConfig builds the limiter the RATE_LIMIT_* settings
+// describe.
+func NewLimiterFromConfig(ctx context.Context, cfg *config.Config, db *database.Mongo) (*Limiter, error) {
+	fallback, err := ParseLimit(cfg.RateLimitDefault)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	routes, err := ParseRoutes(cfg.RateLimitRoutes)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+	}
+	store, err := NewStore(ctx, cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	return NewLimiter(store, fallback, routes), nil
+}
+
+// Allow charges client for a request to route (an HTTP route pattern such as
+// "GET /products/{id}" or a gRPC full method name). ok is false when the
+// route is unlimited.
+func (l *Limiter) Allow(ctx context.Context, route, client string) (res Result, limit Limit, ok bool, err error) {
+	limit, ok = l.routes[route]
+	if !ok {
+		route, limit = "*", l.fallback
+	}
+	if limit.Unlimited() {
+		return Result{Allowed: true}, limit, false, nil
+	}
+	res, err = l.store.Take(ctx, route+"|"+client, limit)
+	return res, limit, true, err
+}
+
+// ClientKey identifies the client of a request: its principal when it
+// authenticated (an API key's subject names the key), else its remote IP.
+func ClientKey(ctx context.Context, remoteAddr string) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return "sub:" + p.Subject
+	}
+	return "ip:" + remoteAddr
+}
+
+// ParseLimit parses "<count>/<unit>[:<burst>]", e.g. "20/s:40" or "600/m";
+// the unit is s, m or h and the burst defaults to the count. "" and "0"
+// mean unlimited.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+	spec, burstStr, hasBurst := strings.Cut(s, ":")
+	countStr, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: want <count>/<unit>[:<burst>]", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: count must be a positive integer", s)
+	}
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("limit %q: unit must be s, m or h", s)
+	}
+	burst := count
+	if hasBurst {
+		if burst, err = strconv.Atoi(burstStr); err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("limit %q: burst must be a positive integer", s)
+		}
+	}
+	return Limit{Rate: float64(count) / per.Seconds(), Burst: burst}, nil
+}
+
+// ParseRoutes parses comma-separated "<route>=<limit>" pairs, e.g.
+// "POST /products=5/s:10,/product.ProductService/Create=5/s:10".
+func ParseRoutes(s string) (map[string]Limit, error) {
+	routes := map[string]Limit{}
+	var errs []error
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		i := strings.LastIndexByte(pair, '=')
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("%q: want <route>=<limit>", pair))
+			continue
+		}
+		route := strings.TrimSpace(pair[:i])
+		limit, err := ParseLimit(pair[i+1:])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", route, err))
+			continue
+		}
+		routes[route] = limit
+	}
+	return routes, errors.Join(errs...)
+}
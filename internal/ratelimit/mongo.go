@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+// MongoStore keeps buckets in a collection so every replica draws from the
+// same ones. Each Take is a single atomic update evaluated with the server's
+// clock, so replicas with skewed clocks still agree. Buckets expire through
+// a TTL index once they would have refilled.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+var MongoStoreTracer = otel.Tracer("RateLimitMongoStore")
+
+type mongoBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// NewMongoStore uses the named collection, creating its TTL index.
+func NewMongoStore(ctx context.Context, db *database.Mongo, name string) (*MongoStore, error) {
+	collection := db.Database.Collection(name)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MongoStore{collection: collection}, nil
+}
+
+func (s *MongoStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	ctx, span := MongoStoreTracer.Start(ctx, "RateLimitMongoStore.Take")
+	defer span.End()
+	logger.Info(ctx, "RateLimitMongoStore.Take")
+
+	burst := float64(limit.Burst)
+	elapsedSec := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$updated_at", "$$NOW"}}}},
+		1000,
+	}}
+	hasToken := bson.M{"$gte": bson.A{"$tokens", 1}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tokens": bson.M{"$min": bson.A{burst, bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$tokens", burst}},
+				bson.M{"$multiply": bson.A{elapsedSec, limit.Rate}},
+			}}}},
+			"updated_at": "$$NOW",
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"allowed":    hasToken,
+			"tokens":     bson.M{"$cond": bson.A{hasToken, bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": bson.M{"$add": bson.A{"$$NOW", limit.Window().Milliseconds()}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var b mongoBucket
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another replica created the bucket first; it exists now.
+		err = s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return result(b.Allowed, b.Tokens, limit), nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+var APIKeyRepositoryTracer = otel.Tracer("APIKeyRepository")
+
+// NewAPIKeyRepository uses the api_key collection, creating the unique index
+// on prefix that every authenticated request looks keys up by.
+func NewAPIKeyRepository(ctx context.Context, db *database.Mongo) (*APIKeyRepository, error) {
+	collection := db.Database.Collection("api_key")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "prefix", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyRepository{collection: collection}, nil
+}
+
+func (r *APIKeyRepository) Insert(ctx context.Context, key *model.APIKey) error {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.Insert")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.Insert")
+
+	key.ID = primitive.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]model.APIKey, error) {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.FindAll")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.FindAll")
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []model.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.APIKey, error) {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.FindByID")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.FindByID")
+
+	var key model.APIKey
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByPrefix looks a key up by the public part of the presented key.
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.FindByPrefix")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.FindByPrefix")
+
+	var key model.APIKey
+	if err := r.collection.FindOne(ctx, bson.M{"prefix": prefix}).Decode(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Rotate replaces the key hash, keeping the old one valid until
+// previousExpiresAt. Revoked keys cannot be rotated.
+func (r *APIKeyRepository) Rotate(ctx context.Context, id primitive.ObjectID, hash string, previousExpiresAt, at time.Time) (*model.APIKey, error) {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.Rotate")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.Rotate")
+
+	// The pipeline form reads the current hash before overwriting it.
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"previous_hash":       "$hash",
+		"previous_expires_at": previousExpiresAt,
+		"hash":                hash,
+		"rotated_at":          at,
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var key model.APIKey
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}}, update, opts).Decode(&key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke marks the key revoked. The document is kept for auditing.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) (*model.APIKey, error) {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.Revoke")
+	defer span.End()
+	logger.Info(ctx, "APIKeyRepository.Revoke")
+
+	update := bson.M{
+		"$set":   bson.M{"revoked_at": at},
+		"$unset": bson.M{"previous_hash": "", "previous_expires_at": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var key model.APIKey
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}}, update, opts).Decode(&key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchLastUsed records that the key authenticated a request at at.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, span := APIKeyRepositoryTracer.Start(ctx, "APIKeyRepository.TouchLastUsed")
+	defer span.End()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$max": bson.M{"last_used_at": at}})
+	return err
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+type PriceRepository struct {
+	schedules *mongo.Collection
+	history   *mongo.Collection
+}
+
+var PriceRepositoryTracer = otel.Tracer("PriceRepository")
+
+// NewPriceRepository uses the price_schedule and price_history collections,
+// creating the indexes the scheduler's claim and the history lookups need,
+// and the unique one on (schedule_id, source) that makes replayed schedule
+// steps upsert a single entry even when two replicas race. That one is
+// partial rather than sparse, as every entry has a source; manual changes
+// have no schedule_id and stay out of it.
+func NewPriceRepository(ctx context.Context, db *database.Mongo) (*PriceRepository, error) {
+	schedules := db.Database.Collection("price_schedule")
+	_, err := schedules.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "starts_at", Value: 1}, {Key: "locked_until", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "ends_at", Value: 1}, {Key: "locked_until", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := db.Database.Collection("price_history")
+	_, err = history.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "effective_from", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "source", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"schedule_id": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PriceRepository{schedules: schedules, history: history}, nil
+}
+
+func (r *PriceRepository) InsertSchedule(ctx context.Context, s *model.PriceSchedule) error {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.InsertSchedule")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.InsertSchedule")
+
+	s.ID = primitive.NewObjectID()
+	_, err := r.schedules.InsertOne(ctx, s)
+	return err
+}
+
+// FindSchedules lists the schedules of a product by start time. With
+// statuses it only returns schedules in one of those states.
+func (r *PriceRepository) FindSchedules(ctx context.Context, productID primitive.ObjectID, statuses ...string) ([]model.PriceSchedule, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.FindSchedules")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.FindSchedules")
+
+	filter := bson.M{"product_id": productID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}})
+	cursor, err := r.schedules.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	schedules := []model.PriceSchedule{}
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *PriceRepository) FindScheduleByID(ctx context.Context, id primitive.ObjectID) (*model.PriceSchedule, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.FindScheduleByID")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.FindScheduleByID")
+
+	var s model.PriceSchedule
+	if err := r.schedules.FindOne(ctx, bson.M{"_id": id}).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// TransitionSchedule moves a schedule from status `from` and applies set in
+// the same update, releasing its lease. The status guard makes every
+// transition happen at most once; it returns mongo.ErrNoDocuments when the
+// schedule is no longer in `from`.
+func (r *PriceRepository) TransitionSchedule(ctx context.Context, id primitive.ObjectID, from string, set bson.M) (*model.PriceSchedule, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.TransitionSchedule")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.TransitionSchedule")
+
+	update := bson.M{}
+	for k, v := range set {
+		update[k] = v
+	}
+	update["locked_until"] = time.Time{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var s model.PriceSchedule
+	err := r.schedules.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": from}, bson.M{"$set": update}, opts).Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SetPreviousPrice remembers the price to restore, unless an earlier attempt
+// already did. It returns the schedule as stored.
+func (r *PriceRepository) SetPreviousPrice(ctx context.Context, id primitive.ObjectID, price float64) (*model.PriceSchedule, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.SetPreviousPrice")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.SetPreviousPrice")
+
+	_, err := r.schedules.UpdateOne(ctx,
+		bson.M{"_id": id, "previous_price": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"previous_price": price}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.FindScheduleByID(ctx, id)
+}
+
+// ClaimDueSchedule leases the next schedule that has to start or end, so
+// replicas never work on the same schedule concurrently. It returns
+// mongo.ErrNoDocuments when nothing is due.
+func (r *PriceRepository) ClaimDueSchedule(ctx context.Context, now time.Time, lease time.Duration) (*model.PriceSchedule, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.ClaimDueSchedule")
+	defer span.End()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": model.ScheduleScheduled, "starts_at": bson.M{"$lte": now}},
+			bson.M{"status": model.ScheduleActive, "ends_at": bson.M{"$lte": now}},
+		},
+		"locked_until": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "starts_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var s model.PriceSchedule
+	if err := r.schedules.FindOneAndUpdate(ctx, filter, update, opts).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// InsertPriceChange appends a history entry. Entries tied to a schedule are
+// upserted on (schedule, source), so replaying a schedule step records it
+// once.
+func (r *PriceRepository) InsertPriceChange(ctx context.Context, c *model.PriceChange) error {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.InsertPriceChange")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.InsertPriceChange")
+
+	if c.ScheduleID == nil {
+		c.ID = primitive.NewObjectID()
+		_, err := r.history.InsertOne(ctx, c)
+		return err
+	}
+	filter := bson.M{"schedule_id": c.ScheduleID, "source": c.Source}
+	update := bson.M{"$setOnInsert": bson.M{
+		"product_id":     c.ProductID,
+		"price":          c.Price,
+		"effective_from": c.EffectiveFrom,
+	}}
+	_, err := r.history.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// PriceAt returns the change in effect at `at`, or mongo.ErrNoDocuments when
+// no price was recorded by then.
+func (r *PriceRepository) PriceAt(ctx context.Context, productID primitive.ObjectID, at time.Time) (*model.PriceChange, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.PriceAt")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.PriceAt")
+
+	filter := bson.M{"product_id": productID, "effective_from": bson.M{"$lte": at}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_from", Value: -1}, {Key: "_id", Value: -1}})
+
+	var c model.PriceChange
+	if err := r.history.FindOne(ctx, filter, opts).Decode(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindHistory returns the changes effective at any point in [from, to],
+// oldest first, with EffectiveTo filled in. Zero bounds are open.
+func (r *PriceRepository) FindHistory(ctx context.Context, productID primitive.ObjectID, from, to time.Time) ([]model.PriceChange, error) {
+	ctx, span := PriceRepositoryTracer.Start(ctx, "PriceRepository.FindHistory")
+	defer span.End()
+	logger.Info(ctx, "PriceRepository.FindHistory")
+
+	rng := bson.M{}
+	if !from.IsZero() {
+		// Start at the change that was already in effect at `from`.
+		start := from
+		if c, err := r.PriceAt(ctx, productID, from); err == nil {
+			start = c.EffectiveFrom
+		} else if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		rng["$gte"] = start
+	}
+	if !to.IsZero() {
+		rng["$lte"] = to
+	}
+	filter := bson.M{"product_id": productID}
+	if len(rng) > 0 {
+		filter["effective_from"] = rng
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "effective_from", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.history.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []model.PriceChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+
+	// EffectiveTo of the last change comes from the first one after `to`.
+	var next *model.PriceChange
+	if n := len(changes); n > 0 && !to.IsZero() {
+		after := bson.M{"product_id": productID, "effective_from": bson.M{"$gt": to}}
+		var c model.PriceChange
+		err := r.history.FindOne(ctx, after, options.FindOne().SetSort(bson.D{{Key: "effective_from", Value: 1}})).Decode(&c)
+		if err == nil {
+			next = &c
+		} else if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+	}
+	for i := range changes {
+		if i+1 < len(changes) {
+			changes[i].EffectiveTo = &changes[i+1].EffectiveFrom
+		} else if next != nil {
+			changes[i].EffectiveTo = &next.EffectiveFrom
+		}
+	}
+	return changes, nil
+}
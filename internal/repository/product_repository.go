@@ -2,25 +2,42 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
+	"simple-crud/internal/database"
 	"simple-crud/internal/logger"
 	"simple-crud/internal/model"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/otel"
 )
 
 type ProductRepository struct {
 	collection *mongo.Collection
+	// listCollection carries the list read preference/concern so scans can
+	// be served by secondaries without affecting point reads.
+	listCollection *mongo.Collection
+	// changes holds the collection's change marker, bumped after every
+	// write; see ChangeMarker.
+	changes *mongo.Collection
 }
 
+// changeMarkerID is the _id of the product collection's change marker.
+const changeMarkerID = "product"
+
 var ProductRepositoryTracer = otel.Tracer("ProductRepository")
 
-func NewProductRepository(db *mongo.Database) *ProductRepository {
+func NewProductRepository(db *database.Mongo) *ProductRepository {
 	return &ProductRepository{
-		collection: db.Collection("product"),
+		collection:     db.Database.Collection("product"),
+		listCollection: db.Database.Collection("product", db.ListCollectionOptions()),
+		changes:        db.Database.Collection("collection_changes", db.ListCollectionOptions()),
 	}
 }
 
@@ -30,8 +47,12 @@ func (r *ProductRepository) Insert(ctx context.Context, product *model.Product)
 	logger.Info(ctx, "ProductRepository.Insert")
 
 	product.ID = primitive.NewObjectID()
-	_, err := r.collection.InsertOne(ctx, product)
-	return err
+	product.UpdatedAt = now()
+	if _, err := r.collection.InsertOne(ctx, product); err != nil {
+		return err
+	}
+	r.touch(ctx)
+	return nil
 }
 
 func (r *ProductRepository) FindAll(ctx context.Context) ([]model.Product, error) {
@@ -39,7 +60,7 @@ func (r *ProductRepository) FindAll(ctx context.Context) ([]model.Product, error
 	defer span.End()
 	logger.Info(ctx, "ProductRepository.FindAll")
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.listCollection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +77,44 @@ func (r *ProductRepository) FindAll(ctx context.Context) ([]model.Product, error
 	return products, nil
 }
 
+// Stream iterates all products with a cursor and hands them to fn in batches
+// of batchSize, so callers never hold the whole catalog in memory. Iteration
+// stops at the first error returned by fn or when ctx is cancelled.
+func (r *ProductRepository) Stream(ctx context.Context, batchSize int, fn func([]model.Product) error) error {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.Stream")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.Stream")
+
+	opts := options.Find().SetBatchSize(int32(batchSize))
+	cursor, err := r.listCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]model.Product, 0, batchSize)
+	for cursor.Next(ctx) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		batch = append(batch, product)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]model.Product, 0, batchSize)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
 func (r *ProductRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Product, error) {
 	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.FindByID")
 	defer span.End()
@@ -69,27 +128,214 @@ func (r *ProductRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	return &product, nil
 }
 
-func (r *ProductRepository) Update(ctx context.Context, id primitive.ObjectID, updated *model.Product) error {
+// Update overwrites the product's editable fields and returns the product as
+// stored. It returns mongo.ErrNoDocuments when the product does not exist.
+func (r *ProductRepository) Update(ctx context.Context, id primitive.ObjectID, updated *model.Product) (*model.Product, error) {
 	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.Update")
 	defer span.End()
 	logger.Info(ctx, "ProductRepository.Update")
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":  updated.Name,
-			"price": updated.Price,
-			"stock": updated.Stock,
+			"name":     updated.Name,
+			"price":    updated.Price,
+			"stock":    updated.Stock,
+			"category": updated.Category,
+			"tags":     updated.Tags,
 		},
+		"$currentDate": bson.M{"updated_at": true},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var product model.Product
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&product); err != nil {
+		return nil, err
 	}
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
-	return err
+	r.touch(ctx)
+	return &product, nil
 }
 
+// Delete removes the product. It returns mongo.ErrNoDocuments when the
+// product does not exist.
 func (r *ProductRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.Delete")
 	defer span.End()
 	logger.Info(ctx, "ProductRepository.Delete")
 
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	r.touch(ctx)
+	return nil
+}
+
+// AddAttachment appends attachment metadata to the product. It returns
+// mongo.ErrNoDocuments when the product does not exist.
+func (r *ProductRepository) AddAttachment(ctx context.Context, id primitive.ObjectID, a model.Attachment) error {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.AddAttachment")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.AddAttachment")
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$push":        bson.M{"attachments": a},
+		"$currentDate": bson.M{"updated_at": true},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	r.touch(ctx)
+	return nil
+}
+
+// RemoveAttachment pulls the attachment from the product. It returns
+// mongo.ErrNoDocuments when the product has no such attachment.
+func (r *ProductRepository) RemoveAttachment(ctx context.Context, id primitive.ObjectID, attachmentID string) error {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.RemoveAttachment")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.RemoveAttachment")
+
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "attachments.id": attachmentID},
+		bson.M{
+			"$pull":        bson.M{"attachments": bson.M{"id": attachmentID}},
+			"$currentDate": bson.M{"updated_at": true},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	r.touch(ctx)
+	return nil
+}
+
+// SetPrice overwrites the price. It returns mongo.ErrNoDocuments when the
+// product does not exist.
+func (r *ProductRepository) SetPrice(ctx context.Context, id primitive.ObjectID, price float64) error {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.SetPrice")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.SetPrice")
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":         bson.M{"price": price},
+		"$currentDate": bson.M{"updated_at": true},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	r.touch(ctx)
+	return nil
+}
+
+// SwapPrice sets the price to `to` only while it is still `from`, so a
+// scheduled change never overwrites a concurrent manual edit. It reports
+// whether the price was changed (or already was `to`).
+func (r *ProductRepository) SwapPrice(ctx context.Context, id primitive.ObjectID, from, to float64) (bool, error) {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.SwapPrice")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.SwapPrice")
+
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "price": bson.M{"$in": bson.A{from, to}}},
+		bson.M{
+			"$set":         bson.M{"price": to},
+			"$currentDate": bson.M{"updated_at": true},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	if res.MatchedCount == 0 {
+		return false, nil
+	}
+	r.touch(ctx)
+	return true, nil
+}
+
+// DeleteAll removes every product and returns how many were deleted.
+func (r *ProductRepository) DeleteAll(ctx context.Context) (int64, error) {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.DeleteAll")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.DeleteAll")
+
+	res, err := r.collection.DeleteMany(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	if res.DeletedCount > 0 {
+		r.touch(ctx)
+	}
+	return res.DeletedCount, nil
+}
+
+// Upsert stores the product under its own ID, replacing any existing
+// document. Unlike Insert it keeps the ID, which restores rely on.
+func (r *ProductRepository) Upsert(ctx context.Context, product *model.Product) error {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.Upsert")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.Upsert")
+
+	if product.UpdatedAt.IsZero() {
+		product.UpdatedAt = now()
+	}
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": product.ID}, product, options.Replace().SetUpsert(true)); err != nil {
+		return err
+	}
+	r.touch(ctx)
+	return nil
+}
+
+// ChangeMarker returns the product collection's change marker; its Version
+// is zero until the first write. Read it before listing: a write in between
+// then only makes the listing newer than the marker, never older. It is
+// read like listings, and through the request's causally consistent
+// session, so the listing cannot be older than the marker either.
+func (r *ProductRepository) ChangeMarker(ctx context.Context) (*model.ChangeMarker, error) {
+	ctx, span := ProductRepositoryTracer.Start(ctx, "ProductRepository.ChangeMarker")
+	defer span.End()
+	logger.Info(ctx, "ProductRepository.ChangeMarker")
+
+	var marker model.ChangeMarker
+	err := r.changes.FindOne(ctx, bson.M{"_id": changeMarkerID}).Decode(&marker)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &model.ChangeMarker{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &marker, nil
+}
+
+// touch bumps the change marker after a write. The write has happened by
+// then, so a failure is logged rather than returned; listings are served
+// under the previous marker until the next write.
+func (r *ProductRepository) touch(ctx context.Context) {
+	_, err := r.changes.UpdateOne(ctx,
+		bson.M{"_id": changeMarkerID},
+		bson.M{"$inc": bson.M{"version": 1}, "$currentDate": bson.M{"updated_at": true}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		logger.Error(ctx, "Failed to bump product change marker",
+			slog.String("exception.message", err.Error()),
+			slog.String("exception.type", fmt.Sprintf("%T", err)),
+		)
+	}
+}
+
+// now is the current time at the millisecond precision MongoDB stores.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
 }
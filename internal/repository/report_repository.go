@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+// ReportRepository runs aggregation pipelines over the product collection.
+// Reports are scans, so they always use the list read preference.
+type ReportRepository struct {
+	collection *mongo.Collection
+}
+
+var ReportRepositoryTracer = otel.Tracer("ReportRepository")
+
+var ErrInvalidGroupBy = errors.New("group by must be one of: category, tag")
+
+// otherBucket is the $bucket default for prices that are not numbers.
+const otherBucket = "other"
+
+func NewReportRepository(db *database.Mongo) *ReportRepository {
+	return &ReportRepository{
+		collection: db.Database.Collection("product", db.ListCollectionOptions()),
+	}
+}
+
+// aggregateOptions bounds the server-side execution time by the context
+// deadline, so Mongo stops working on a report nobody is waiting for.
+func aggregateOptions(ctx context.Context) *options.AggregateOptions {
+	opts := options.Aggregate()
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > 0 {
+			opts.SetMaxTime(remaining)
+		}
+	}
+	return opts
+}
+
+func (r *ReportRepository) InventoryValue(ctx context.Context) (*model.InventoryValue, error) {
+	ctx, span := ReportRepositoryTracer.Start(ctx, "ReportRepository.InventoryValue")
+	defer span.End()
+	logger.Info(ctx, "ReportRepository.InventoryValue")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":           nil,
+			"total_value":   bson.M{"$sum": bson.M{"$multiply": bson.A{"$price", "$stock"}}},
+			"total_stock":   bson.M{"$sum": "$stock"},
+			"product_count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline, aggregateOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result model.InventoryValue
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+	return &result, cursor.Err()
+}
+
+func (r *ReportRepository) LowStock(ctx context.Context, threshold int, limit int64) ([]model.Product, error) {
+	ctx, span := ReportRepositoryTracer.Start(ctx, "ReportRepository.LowStock")
+	defer span.End()
+	logger.Info(ctx, "ReportRepository.LowStock")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"stock": bson.M{"$lt": threshold}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "stock", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline, aggregateOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []model.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// PriceHistogram counts products per price bucket. With boundaries it uses
+// fixed buckets [b0,b1), [b1,b2)... plus open-ended buckets for prices outside
+// that range; without them it
+// lets Mongo pick `buckets` evenly populated ranges.
+func (r *ReportRepository) PriceHistogram(ctx context.Context, boundaries []float64, buckets int) ([]model.PriceBucket, error) {
+	ctx, span := ReportRepositoryTracer.Start(ctx, "ReportRepository.PriceHistogram")
+	defer span.End()
+	logger.Info(ctx, "ReportRepository.PriceHistogram")
+
+	if len(boundaries) > 0 {
+		return r.fixedHistogram(ctx, boundaries)
+	}
+	return r.autoHistogram(ctx, buckets)
+}
+
+func (r *ReportRepository) fixedHistogram(ctx context.Context, boundaries []float64) ([]model.PriceBucket, error) {
+	bounds := append([]float64(nil), boundaries...)
+	sort.Float64s(bounds)
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] == bounds[i-1] {
+			return nil, fmt.Errorf("duplicate histogram boundary %v", bounds[i])
+		}
+	}
+
+	// Sentinels turn the prices outside the requested range into open-ended
+	// buckets below the first and above the last boundary. The default must
+	// lie outside the boundaries, so it is a string; it only collects missing
+	// or non-numeric prices, which are left out.
+	bounds = append(append([]float64{-math.MaxFloat64}, bounds...), math.MaxFloat64)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$bucket", Value: bson.M{
+			"groupBy":    "$price",
+			"boundaries": bounds,
+			"default":    otherBucket,
+			"output":     bson.M{"count": bson.M{"$sum": 1}},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline, aggregateOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := map[float64]int64{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID    bson.RawValue `bson:"_id"`
+			Count int64         `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		lower, ok := row.ID.DoubleOK()
+		if !ok {
+			continue
+		}
+		counts[lower] += row.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.PriceBucket, 0, len(bounds)-1)
+	for i := 0; i < len(bounds)-1; i++ {
+		lower, upper := bounds[i], bounds[i+1]
+		bucket := model.PriceBucket{Min: &lower, Max: &upper, Count: counts[lower]}
+		if i == 0 {
+			bucket.Min = nil
+		}
+		if i == len(bounds)-2 {
+			bucket.Max = nil
+		}
+		if (bucket.Min == nil || bucket.Max == nil) && bucket.Count == 0 {
+			continue
+		}
+		result = append(result, bucket)
+	}
+	return result, nil
+}
+
+func (r *ReportRepository) autoHistogram(ctx context.Context, buckets int) ([]model.PriceBucket, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$bucketAuto", Value: bson.M{
+			"groupBy": "$price",
+			"buckets": buckets,
+			"output":  bson.M{"count": bson.M{"$sum": 1}},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline, aggregateOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := []model.PriceBucket{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				Min float64 `bson:"min"`
+				Max float64 `bson:"max"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		lower, upper := row.ID.Min, row.ID.Max
+		result = append(result, model.PriceBucket{Min: &lower, Max: &upper, Count: row.Count})
+	}
+	return result, cursor.Err()
+}
+
+// CountBy groups products by "category" or "tag" and returns count, stock
+// and inventory value per group, largest groups first.
+func (r *ReportRepository) CountBy(ctx context.Context, by string) ([]model.GroupCount, error) {
+	ctx, span := ReportRepositoryTracer.Start(ctx, "ReportRepository.CountBy")
+	defer span.End()
+	logger.Info(ctx, "ReportRepository.CountBy")
+
+	var pipeline mongo.Pipeline
+	switch by {
+	case "category":
+		pipeline = mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{
+				"_id":   bson.M{"$ifNull": bson.A{"$category", ""}},
+				"count": bson.M{"$sum": 1},
+				"stock": bson.M{"$sum": "$stock"},
+				"value": bson.M{"$sum": bson.M{"$multiply": bson.A{"$price", "$stock"}}},
+			}}},
+		}
+	case "tag":
+		pipeline = mongo.Pipeline{
+			{{Key: "$unwind", Value: "$tags"}},
+			{{Key: "$group", Value: bson.M{
+				"_id":   "$tags",
+				"count": bson.M{"$sum": 1},
+				"stock": bson.M{"$sum": "$stock"},
+				"value": bson.M{"$sum": bson.M{"$multiply": bson.A{"$price", "$stock"}}},
+			}}},
+		}
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline, aggregateOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	groups := []model.GroupCount{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
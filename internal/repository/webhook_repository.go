@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+// MaxDeliveryHistory caps the attempts kept on a delivery document.
+const MaxDeliveryHistory = 20
+
+type WebhookRepository struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+}
+
+var WebhookRepositoryTracer = otel.Tracer("WebhookRepository")
+
+// NewWebhookRepository uses the webhook_subscription and webhook_delivery
+// collections, creating the index on (status, next_attempt_at) that the
+// workers claim due deliveries by.
+func NewWebhookRepository(ctx context.Context, db *database.Mongo) (*WebhookRepository, error) {
+	deliveries := db.Database.Collection("webhook_delivery")
+	_, err := deliveries.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookRepository{
+		subscriptions: db.Database.Collection("webhook_subscription"),
+		deliveries:    deliveries,
+	}, nil
+}
+
+func (r *WebhookRepository) InsertSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.InsertSubscription")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.InsertSubscription")
+
+	sub.ID = primitive.NewObjectID()
+	_, err := r.subscriptions.InsertOne(ctx, sub)
+	return err
+}
+
+func (r *WebhookRepository) FindSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.FindSubscriptions")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.FindSubscriptions")
+
+	cursor, err := r.subscriptions.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	subs := []model.WebhookSubscription{}
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// FindSubscriptionsForEvent returns the enabled subscriptions listening to event.
+func (r *WebhookRepository) FindSubscriptionsForEvent(ctx context.Context, event string) ([]model.WebhookSubscription, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.FindSubscriptionsForEvent")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.FindSubscriptionsForEvent")
+
+	cursor, err := r.subscriptions.Find(ctx, bson.M{"disabled": bson.M{"$ne": true}, "events": event})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	subs := []model.WebhookSubscription{}
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) FindSubscriptionByID(ctx context.Context, id primitive.ObjectID) (*model.WebhookSubscription, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.FindSubscriptionByID")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.FindSubscriptionByID")
+
+	var sub model.WebhookSubscription
+	if err := r.subscriptions.FindOne(ctx, bson.M{"_id": id}).Decode(&sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, id primitive.ObjectID, updated *model.WebhookSubscription) error {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.UpdateSubscription")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.UpdateSubscription")
+
+	update := bson.M{
+		"$set": bson.M{
+			"url":        updated.URL,
+			"events":     updated.Events,
+			"secret":     updated.Secret,
+			"disabled":   updated.Disabled,
+			"updated_at": updated.UpdatedAt,
+		},
+	}
+	res, err := r.subscriptions.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription and its pending deliveries.
+// Delivered and dead deliveries are kept for auditing.
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.DeleteSubscription")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.DeleteSubscription")
+
+	res, err := r.subscriptions.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	_, err = r.deliveries.DeleteMany(ctx, bson.M{"subscription_id": id, "status": model.DeliveryPending})
+	return err
+}
+
+func (r *WebhookRepository) InsertDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.InsertDeliveries")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.InsertDeliveries")
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	docs := make([]any, len(deliveries))
+	for i := range deliveries {
+		deliveries[i].ID = primitive.NewObjectID()
+		docs[i] = deliveries[i]
+	}
+	_, err := r.deliveries.InsertMany(ctx, docs)
+	return err
+}
+
+// FindDeliveries lists deliveries of a subscription, newest first. An empty
+// status matches every state.
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, status string, limit int64) ([]model.WebhookDelivery, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.FindDeliveries")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.FindDeliveries")
+
+	filter := bson.M{"subscription_id": subscriptionID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []model.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDelivery atomically leases the oldest pending delivery that is due,
+// so several worker replicas never send the same delivery concurrently.
+// It returns mongo.ErrNoDocuments when nothing is due.
+func (r *WebhookRepository) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*model.WebhookDelivery, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.ClaimDueDelivery")
+	defer span.End()
+
+	filter := bson.M{
+		"status":          model.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+		"locked_until":    bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery model.WebhookDelivery
+	if err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RecordAttempt appends an attempt to the delivery and moves it to status.
+// For pending deliveries nextAttemptAt schedules the retry.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt model.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.RecordAttempt")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.RecordAttempt")
+
+	set := bson.M{
+		"status":          status,
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    time.Time{},
+		"last_error":      attempt.Error,
+	}
+	if status == model.DeliveryDelivered {
+		set["delivered_at"] = attempt.At
+	}
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"attempts": 1},
+		"$push": bson.M{"history": bson.M{
+			"$each":  bson.A{attempt},
+			"$slice": -MaxDeliveryHistory,
+		}},
+	}
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// RequeueDelivery moves a dead delivery back to pending with a fresh retry
+// budget.
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, id primitive.ObjectID, now time.Time) (*model.WebhookDelivery, error) {
+	ctx, span := WebhookRepositoryTracer.Start(ctx, "WebhookRepository.RequeueDelivery")
+	defer span.End()
+	logger.Info(ctx, "WebhookRepository.RequeueDelivery")
+
+	update := bson.M{"$set": bson.M{
+		"status":          model.DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+		"locked_until":    time.Time{},
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var delivery model.WebhookDelivery
+	err := r.deliveries.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": model.DeliveryDead}, update, opts).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
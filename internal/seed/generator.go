@@ -0,0 +1,80 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"simple-crud/internal/model"
+)
+
+type category struct {
+	name     string
+	styles   []string
+	nouns    []string
+	minPrice float64
+	maxPrice float64
+}
+
+var categories = []category{
+	{"electronics", []string{"Wireless", "Compact", "Pro", "Smart", "Ultra", "Portable"}, []string{"Headphones", "Speaker", "Charger", "Keyboard", "Mouse", "Monitor", "Webcam", "Router"}, 15, 900},
+	{"home", []string{"Ceramic", "Oak", "Linen", "Steel", "Modern", "Vintage"}, []string{"Lamp", "Kettle", "Blender", "Pillow", "Rug", "Clock", "Vase", "Shelf"}, 8, 350},
+	{"apparel", []string{"Cotton", "Wool", "Leather", "Denim", "Classic", "Slim-Fit"}, []string{"T-Shirt", "Hoodie", "Jacket", "Sneakers", "Cap", "Scarf", "Socks", "Backpack"}, 5, 250},
+	{"grocery", []string{"Organic", "Roasted", "Extra Virgin", "Wildflower", "Artisan", "Dark"}, []string{"Coffee Beans", "Green Tea", "Olive Oil", "Honey", "Pasta", "Granola", "Chocolate", "Syrup"}, 1, 40},
+	{"outdoor", []string{"Ultralight", "Waterproof", "Insulated", "Folding", "Rugged", "Trail"}, []string{"Tent", "Sleeping Bag", "Lantern", "Water Bottle", "Hiking Poles", "Cooler", "Hammock", "Compass"}, 10, 600},
+	{"toys", []string{"Wooden", "Giant", "Mini", "Classic", "Magnetic", "Deluxe"}, []string{"Puzzle", "Building Set", "Plush Bear", "Kite", "Board Game", "Yo-Yo", "Race Car", "Doll House"}, 3, 150},
+}
+
+var (
+	brands  = []string{"Acme", "Northwind", "Bluebird", "Everfield", "Kinto", "Lumen", "Orbit", "Pinecrest", "Riverstone", "Zephyr"}
+	tagPool = []string{"new", "bestseller", "sale", "eco-friendly", "gift", "limited", "bundle", "imported", "handmade", "clearance"}
+)
+
+// Generator builds realistic-looking products. Product i depends only on the
+// seed and i, so a run is reproducible regardless of concurrency or order.
+type Generator struct {
+	seed int64
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{seed: seed}
+}
+
+// Product returns the i-th product of the catalog.
+func (g *Generator) Product(i int) model.Product {
+	rng := rand.New(rand.NewSource(g.seed*1_000_003 + int64(i)))
+	c := categories[rng.Intn(len(categories))]
+
+	name := fmt.Sprintf("%s %s %s", brands[rng.Intn(len(brands))], c.styles[rng.Intn(len(c.styles))], c.nouns[rng.Intn(len(c.nouns))])
+
+	// Log-uniform prices cluster near the cheap end like real catalogs, and
+	// end in .99 or .49 most of the time.
+	price := math.Exp(math.Log(c.minPrice) + rng.Float64()*(math.Log(c.maxPrice)-math.Log(c.minPrice)))
+	switch rng.Intn(4) {
+	case 0:
+		price = math.Round(price)
+	case 1:
+		price = math.Floor(price) + 0.49
+	default:
+		price = math.Floor(price) + 0.99
+	}
+
+	// Most items are in stock; about one in ten is sold out or nearly so.
+	stock := 20 + rng.Intn(480)
+	if rng.Intn(10) == 0 {
+		stock = rng.Intn(5)
+	}
+
+	tags := make([]string, 0, 3)
+	for _, idx := range rng.Perm(len(tagPool))[:rng.Intn(4)] {
+		tags = append(tags, tagPool[idx])
+	}
+
+	return model.Product{
+		Name:     name,
+		Price:    price,
+		Stock:    stock,
+		Category: c.name,
+		Tags:     tags,
+	}
+}
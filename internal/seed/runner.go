@@ -0,0 +1,106 @@
+package seed
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress is a snapshot of a running seed.
+type Progress struct {
+	Done    int64
+	Failed  int64
+	Total   int64
+	Elapsed time.Duration
+}
+
+// Rate is the number of products written per second so far.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Done) / p.Elapsed.Seconds()
+}
+
+type Options struct {
+	Count       int
+	Concurrency int
+	// ReportEvery is the interval between OnProgress calls. OnProgress is
+	// also called once when the run ends.
+	ReportEvery time.Duration
+	OnProgress  func(Progress)
+	// OnError is called for every failed write; the run continues.
+	OnError func(i int, err error)
+}
+
+// Run writes products 0..Count-1 from gen to sink using Concurrency workers.
+// It stops early when ctx is cancelled.
+func Run(ctx context.Context, gen *Generator, sink Sink, opts Options) Progress {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	start := time.Now()
+	var done, failed atomic.Int64
+	snapshot := func() Progress {
+		return Progress{Done: done.Load(), Failed: failed.Load(), Total: int64(opts.Count), Elapsed: time.Since(start)}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				p := gen.Product(i)
+				if err := sink.Create(ctx, &p); err != nil {
+					failed.Add(1)
+					if opts.OnError != nil {
+						opts.OnError(i, err)
+					}
+					continue
+				}
+				done.Add(1)
+			}
+		}()
+	}
+
+	stopReport := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		if opts.OnProgress == nil || opts.ReportEvery <= 0 {
+			return
+		}
+		ticker := time.NewTicker(opts.ReportEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopReport:
+				return
+			case <-ticker.C:
+				opts.OnProgress(snapshot())
+			}
+		}
+	}()
+
+feed:
+	for i := 0; i < opts.Count; i++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(stopReport)
+	<-reported
+
+	final := snapshot()
+	if opts.OnProgress != nil {
+		opts.OnProgress(final)
+	}
+	return final
+}
@@ -0,0 +1,123 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"simple-crud/internal/client"
+	pb "simple-crud/internal/handler/grpc/pb"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+	"simple-crud/internal/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Sink is where generated products are written.
+type Sink interface {
+	Create(ctx context.Context, p *model.Product) error
+	// Wipe deletes every existing product and returns how many it removed.
+	Wipe(ctx context.Context) (int, error)
+}
+
+// RepositorySink writes straight to the repository. It is the fastest mode
+// but skips service side effects such as webhooks and price history.
+type RepositorySink struct {
+	repo *repository.ProductRepository
+}
+
+func NewRepositorySink(repo *repository.ProductRepository) *RepositorySink {
+	return &RepositorySink{repo: repo}
+}
+
+func (s *RepositorySink) Create(ctx context.Context, p *model.Product) error {
+	return s.repo.Insert(ctx, p)
+}
+
+func (s *RepositorySink) Wipe(ctx context.Context) (int, error) {
+	n, err := s.repo.DeleteAll(ctx)
+	return int(n), err
+}
+
+// HTTPSink goes through the HTTP API, exercising the full server stack.
+type HTTPSink struct {
+	client *client.HTTPClient
+}
+
+func NewHTTPSink(c *client.HTTPClient) *HTTPSink {
+	return &HTTPSink{client: c}
+}
+
+func (s *HTTPSink) Create(ctx context.Context, p *model.Product) error {
+	resp, err := s.client.PostWithResponse("/products", p, client.RequestOptions{Context: ctx})
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("create product: HTTP %d: %s", resp.StatusCode, resp.RawBody)
+	}
+	return nil
+}
+
+func (s *HTTPSink) Wipe(ctx context.Context) (int, error) {
+	// Only the IDs are needed; the gateway encodes int64 fields (attachment
+	// sizes) as JSON strings, which model.Product would not decode.
+	var products []struct {
+		ID string `json:"id"`
+	}
+	if err := s.client.Get("/products", &products, client.RequestOptions{Context: ctx}); err != nil {
+		return 0, err
+	}
+	for i, p := range products {
+		resp, err := s.client.DeleteWithResponse("/products/"+p.ID, client.RequestOptions{Context: ctx})
+		if err != nil {
+			return i, err
+		}
+		if !resp.IsSuccess() {
+			return i, fmt.Errorf("delete product %s: HTTP %d", p.ID, resp.StatusCode)
+		}
+	}
+	return len(products), nil
+}
+
+// GRPCSink goes through the gRPC API.
+type GRPCSink struct {
+	client pb.ProductServiceClient
+}
+
+func NewGRPCSink(c pb.ProductServiceClient) *GRPCSink {
+	return &GRPCSink{client: c}
+}
+
+// outgoing propagates the trace context to the server.
+func outgoing(ctx context.Context) context.Context {
+	md := metadata.New(nil)
+	otel.GetTextMapPropagator().Inject(ctx, telemetry.MetadataTextMapCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (s *GRPCSink) Create(ctx context.Context, p *model.Product) error {
+	_, err := s.client.Create(outgoing(ctx), &pb.Product{
+		Name:     p.Name,
+		Price:    p.Price,
+		Stock:    int32(p.Stock),
+		Category: p.Category,
+		Tags:     p.Tags,
+	})
+	return err
+}
+
+func (s *GRPCSink) Wipe(ctx context.Context) (int, error) {
+	res, err := s.client.GetAll(outgoing(ctx), &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	for i, p := range res.GetProducts() {
+		if _, err := s.client.Delete(outgoing(ctx), &pb.ProductId{Id: p.GetId()}); err != nil {
+			return i, err
+		}
+	}
+	return len(res.GetProducts()), nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/cache"
+	"simple-crud/internal/config"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrAPIKeyRevoked  = errors.New("API key is revoked")
+)
+
+const (
+	// apiKeyPrefix marks the keys this service issues, so they are easy to
+	// spot in configs and secret scanners.
+	apiKeyPrefix = "sck_"
+	// MaxAPIKeyRotationGrace bounds how long a rotated-out key stays valid.
+	MaxAPIKeyRotationGrace = 7 * 24 * time.Hour
+	// apiKeyTouchInterval throttles last_used_at writes per key.
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService struct {
+	repo    *repository.APIKeyRepository
+	touched *cache.TTL[bool]
+}
+
+var APIKeyServiceTracer = otel.Tracer("APIKeyService")
+
+func NewAPIKeyService(repo *repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:    repo,
+		touched: cache.NewTTL[bool](apiKeyTouchInterval),
+	}
+}
+
+func validateAPIKey(key *model.APIKey, now time.Time) error {
+	var v validator
+	v.check(strings.TrimSpace(key.Name) != "", "name", "is required")
+	v.check(len(key.Scopes) > 0, "scopes", "at least one scope is required")
+	for i, s := range key.Scopes {
+		v.check(slices.Contains(auth.KnownScopes, s),
+			fmt.Sprintf("scopes[%d]", i), fmt.Sprintf("unknown scope %q", s))
+	}
+	v.check(key.ExpiresAt == nil || key.ExpiresAt.After(now), "expires_at", "must be in the future")
+	return v.err(ErrInvalidAPIKey)
+}
+
+func apiKeyID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: invalid ID format", ErrInvalidAPIKey)
+	}
+	return objID, nil
+}
+
+// newAPIKey returns a fresh secret for prefix as "sck_<prefix>_<secret>".
+func newAPIKey(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create issues a key. The plaintext key is only returned here.
+func (s *APIKeyService) Create(ctx context.Context, key *model.APIKey) (*model.APIKey, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.Create")
+	defer span.End()
+	logger.Info(ctx, "APIKeyService.Create")
+
+	now := time.Now().UTC()
+	if err := validateAPIKey(key, now); err != nil {
+		return nil, err
+	}
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key.Prefix = hex.EncodeToString(b)
+	plain, err := newAPIKey(key.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	key.Hash = hashAPIKey(plain)
+	key.CreatedAt = now
+
+	if err := s.repo.Insert(ctx, key); err != nil {
+		return nil, err
+	}
+	key.Key = plain
+	return key, nil
+}
+
+func (s *APIKeyService) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.GetAll")
+	defer span.End()
+	logger.Info(ctx, "APIKeyService.GetAll")
+
+	return s.repo.FindAll(ctx)
+}
+
+func (s *APIKeyService) GetByID(ctx context.Context, id string) (*model.APIKey, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.GetByID")
+	defer span.End()
+	logger.Info(ctx, "APIKeyService.GetByID")
+
+	objID, err := apiKeyID(id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.repo.FindByID(ctx, objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// Rotate issues a new secret for the key. The previous one keeps working for
+// grace, so callers can roll the new key out first.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (*model.APIKey, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.Rotate")
+	defer span.End()
+	logger.Info(ctx, "APIKeyService.Rotate")
+
+	objID, err := apiKeyID(id)
+	if err != nil {
+		return nil, err
+	}
+	if grace < 0 || grace > MaxAPIKeyRotationGrace {
+		return nil, &ValidationError{Err: ErrInvalidAPIKey, Fields: []FieldError{
+			{Field: "grace_period_sec", Message: fmt.Sprintf("must be between 0 and %d", int64(MaxAPIKeyRotationGrace.Seconds()))},
+		}}
+	}
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	plain, err := newAPIKey(current.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	key, err := s.repo.Rotate(ctx, objID, hashAPIKey(plain), now.Add(grace), now)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Revoked (or deleted) since it was read.
+		return nil, ErrAPIKeyRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	key.Key = plain
+	return key, nil
+}
+
+// Revoke disables the key for good. Revoking a revoked key is a no-op.
+func (s *APIKeyService) Revoke(ctx context.Context, id string) (*model.APIKey, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+	logger.Info(ctx, "APIKeyService.Revoke")
+
+	objID, err := apiKeyID(id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.repo.Revoke(ctx, objID, time.Now().UTC())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return s.GetByID(ctx, id)
+	}
+	return key, err
+}
+
+// VerifyAPIKey implements auth.APIKeyVerifier. Rejections wrap
+// auth.ErrInvalidToken; database errors are returned as they are.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, raw string) (*auth.Principal, error) {
+	ctx, span := APIKeyServiceTracer.Start(ctx, "APIKeyService.VerifyAPIKey")
+	defer span.End()
+
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(raw, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, fmt.Errorf("%w: malformed API key", auth.ErrInvalidToken)
+	}
+	key, err := s.repo.FindByPrefix(ctx, prefix)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: unknown API key", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	hash := hashAPIKey(raw)
+	matches := subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) == 1
+	if !matches && key.PreviousHash != "" && key.PreviousExpiresAt != nil && now.Before(*key.PreviousExpiresAt) {
+		matches = subtle.ConstantTimeCompare([]byte(hash), []byte(key.PreviousHash)) == 1
+	}
+	switch {
+	case !matches:
+		return nil, fmt.Errorf("%w: API key does not match", auth.ErrInvalidToken)
+	case key.RevokedAt != nil:
+		return nil, fmt.Errorf("%w: API key is revoked", auth.ErrInvalidToken)
+	case key.ExpiresAt != nil && !now.Before(*key.ExpiresAt):
+		return nil, fmt.Errorf("%w: API key is expired", auth.ErrInvalidToken)
+	}
+
+	s.touch(ctx, key.ID, now)
+
+	p := &auth.Principal{
+		Method:  auth.MethodAPIKey,
+		Subject: "api-key:" + key.ID.Hex(),
+		Scopes:  key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		p.ExpiresAt = *key.ExpiresAt
+	}
+	return p, nil
+}
+
+// touch records last use at most once per apiKeyTouchInterval per key; a
+// failure is logged and does not fail the request.
+func (s *APIKeyService) touch(ctx context.Context, id primitive.ObjectID, now time.Time) {
+	if _, ok := s.touched.Get(id.Hex()); ok {
+		return
+	}
+	s.touched.Set(id.Hex(), true)
+	if err := s.repo.TouchLastUsed(ctx, id, now); err != nil {
+		logger.Error(ctx, "Failed to record API key use", slog.String("exception.message", err.Error()))
+	}
+}
+
+// NewAuthenticator accepts the service's API keys and, when a secret or key
+// set is configured, bearer JWTs.
+func NewAuthenticator(cfg *config.Config, apiKeys *APIKeyService) (*auth.Authenticator, error) {
+	a := &auth.Authenticator{APIKeys: apiKeys}
+	if cfg.AuthHS256Secret == "" && cfg.AuthJWKS == "" {
+		return a, nil
+	}
+	verifier, err := auth.NewVerifier(auth.Config{
+		Issuer:      cfg.AuthIssuer,
+		Audience:    cfg.AuthAudience,
+		HS256Secret: []byte(cfg.AuthHS256Secret),
+		JWKS:        cfg.AuthJWKS,
+		JWKSRefresh: time.Duration(cfg.AuthJWKSRefreshSec) * time.Second,
+		Leeway:      time.Duration(cfg.AuthLeewaySec) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Tokens = verifier
+	return a, nil
+}
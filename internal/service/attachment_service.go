@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+	"simple-crud/internal/storage"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrInvalidAttachment  = errors.New("invalid attachment request")
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+	ErrAttachmentType     = errors.New("attachment content type not allowed")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrProductNotFound    = errors.New("product not found")
+)
+
+// maxFilenameLen bounds the stored filename; longer names are truncated.
+const maxFilenameLen = 255
+
+type AttachmentConfig struct {
+	MaxBytes     int64
+	AllowedTypes []string
+	// SniffContent detects the type from the first 512 bytes instead of
+	// trusting the type declared by the client.
+	SniffContent bool
+}
+
+func NewAttachmentConfig(cfg *config.Config) AttachmentConfig {
+	var allowed []string
+	for _, t := range strings.Split(cfg.AttachmentAllowedTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			allowed = append(allowed, t)
+		}
+	}
+	return AttachmentConfig{
+		MaxBytes:     cfg.AttachmentMaxBytes,
+		AllowedTypes: allowed,
+		SniffContent: cfg.AttachmentSniffContent,
+	}
+}
+
+type AttachmentService struct {
+	products *repository.ProductRepository
+	store    storage.BlobStore
+	cfg      AttachmentConfig
+	guard    ProductGuard
+}
+
+var AttachmentServiceTracer = otel.Tracer("AttachmentService")
+
+func NewAttachmentService(products *repository.ProductRepository, store storage.BlobStore, cfg AttachmentConfig) *AttachmentService {
+	return &AttachmentService{products: products, store: store, cfg: cfg}
+}
+
+// SetProductGuard makes listing and downloading subject to the policy as
+// reads of the product, and uploads and deletions as updates of its
+// attachments.
+func (s *AttachmentService) SetProductGuard(g ProductGuard) {
+	s.guard = g
+}
+
+func (s *AttachmentService) authorize(ctx context.Context, p *model.Product) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.AuthorizeChange(ctx, p, "attachments")
+}
+
+func (s *AttachmentService) authorizeRead(ctx context.Context, p *model.Product) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.AuthorizeRead(ctx, p)
+}
+
+// MaxBytes is the largest accepted attachment.
+func (s *AttachmentService) MaxBytes() int64 {
+	return s.cfg.MaxBytes
+}
+
+// allowed reports whether contentType (parameters ignored) is accepted. An
+// empty allow list accepts everything.
+func (s *AttachmentService) allowed(contentType string) bool {
+	if len(s.cfg.AllowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(s.cfg.AllowedTypes, mediaType)
+}
+
+// cleanFilename drops any client-side directory and control characters.
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		name = "attachment"
+	}
+	if len(name) > maxFilenameLen {
+		name = name[:maxFilenameLen]
+	}
+	return name
+}
+
+func productID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: invalid ID format", ErrInvalidAttachment)
+	}
+	return objID, nil
+}
+
+// Upload stores r as an attachment of the product. The content type is
+// sniffed (or taken from declaredType when sniffing is off) and checked
+// against the allow list before anything is written.
+func (s *AttachmentService) Upload(ctx context.Context, id, filename, declaredType string, r io.Reader) (*model.Attachment, error) {
+	ctx, span := AttachmentServiceTracer.Start(ctx, "AttachmentService.Upload")
+	defer span.End()
+	logger.Info(ctx, "AttachmentService.Upload")
+
+	objID, err := productID(id)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.products.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorize(ctx, product); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	contentType := declaredType
+	if s.cfg.SniffContent || contentType == "" {
+		head, err := br.Peek(512)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		contentType = http.DetectContentType(head)
+	}
+	span.SetAttributes(attribute.String("attachment.content_type", contentType))
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentType, contentType)
+	}
+
+	a := model.Attachment{
+		ID:          primitive.NewObjectID().Hex(),
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		UploadedAt:  time.Now().UTC(),
+	}
+	// Read one byte past the limit so an oversized upload is detectable.
+	n, err := s.store.Put(ctx, a.ID, a.Filename, io.LimitReader(br, s.cfg.MaxBytes+1))
+	if err != nil {
+		s.discard(ctx, a.ID)
+		return nil, err
+	}
+	if n > s.cfg.MaxBytes {
+		s.discard(ctx, a.ID)
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.cfg.MaxBytes)
+	}
+	a.Size = n
+
+	if err := s.products.AddAttachment(ctx, objID, a); err != nil {
+		s.discard(ctx, a.ID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// List returns the attachment metadata of the product.
+func (s *AttachmentService) List(ctx context.Context, id string) ([]model.Attachment, error) {
+	ctx, span := AttachmentServiceTracer.Start(ctx, "AttachmentService.List")
+	defer span.End()
+	logger.Info(ctx, "AttachmentService.List")
+
+	objID, err := productID(id)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.products.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorizeRead(ctx, product); err != nil {
+		return nil, err
+	}
+	if product.Attachments == nil {
+		return []model.Attachment{}, nil
+	}
+	return product.Attachments, nil
+}
+
+// Open returns the attachment metadata and its content. The caller must
+// close the blob.
+func (s *AttachmentService) Open(ctx context.Context, id, attachmentID string) (*model.Attachment, storage.Blob, error) {
+	ctx, span := AttachmentServiceTracer.Start(ctx, "AttachmentService.Open")
+	defer span.End()
+	logger.Info(ctx, "AttachmentService.Open")
+
+	attachments, err := s.List(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := slices.IndexFunc(attachments, func(a model.Attachment) bool { return a.ID == attachmentID })
+	if i < 0 {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	blob, err := s.store.Open(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return &attachments[i], blob, nil
+}
+
+// Delete detaches the attachment first, so the product never points at a
+// missing blob, then removes the blob.
+func (s *AttachmentService) Delete(ctx context.Context, id, attachmentID string) error {
+	ctx, span := AttachmentServiceTracer.Start(ctx, "AttachmentService.Delete")
+	defer span.End()
+	logger.Info(ctx, "AttachmentService.Delete")
+
+	objID, err := productID(id)
+	if err != nil {
+		return err
+	}
+	if s.guard != nil {
+		product, err := s.products.FindByID(ctx, objID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrProductNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, product); err != nil {
+			return err
+		}
+	}
+	if err := s.products.RemoveAttachment(ctx, objID, attachmentID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	s.discard(ctx, attachmentID)
+	return nil
+}
+
+// discard removes a blob on a best-effort basis; failures only leave an
+// orphan behind, so they are logged rather than returned.
+func (s *AttachmentService) discard(ctx context.Context, attachmentID string) {
+	if err := s.store.Delete(ctx, attachmentID); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+		trace.SpanFromContext(ctx).RecordError(err)
+		logger.Error(ctx, "Failed to delete attachment blob",
+			slog.String("data.attachment_id", attachmentID),
+			slog.String("exception.message", err.Error()),
+		)
+	}
+}
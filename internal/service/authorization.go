@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"simple-crud/internal/auth"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/policy"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrForbidden is returned when the policy denies the caller an action.
+var ErrForbidden = errors.New("permission denied")
+
+// Authorizer decides whether the caller in ctx may act on products; see
+// policy.Store.
+type Authorizer interface {
+	Authorize(ctx context.Context, req policy.Request) policy.Decision
+	// Digest identifies the policy in force for the audit log.
+	Digest() string
+}
+
+// ProductGuard authorizes reads and changes of products made outside
+// ProductService, so the policy covers them as well; *ProductService
+// implements it.
+type ProductGuard interface {
+	AuthorizeRead(ctx context.Context, p *model.Product) error
+	AuthorizeChange(ctx context.Context, p *model.Product, fields ...string) error
+}
+
+// AuthorizeRead checks that the caller may read p, or, with a nil p, the
+// catalog as a whole.
+func (s *ProductService) AuthorizeRead(ctx context.Context, p *model.Product) error {
+	return s.authorize(ctx, policy.ActionProductRead, p, nil)
+}
+
+// AuthorizeChange checks that the caller may update fields of p, as if
+// through Update.
+func (s *ProductService) AuthorizeChange(ctx context.Context, p *model.Product, fields ...string) error {
+	return s.authorize(ctx, policy.ActionProductUpdate, p, fields)
+}
+
+// authorize asks the authorizer, if any, whether the caller may perform
+// action on p (nil for list reads), changing fields. Denials are audit
+// logged and returned as ErrForbidden; the caller sees the action and
+// fields, never the roles or rule.
+func (s *ProductService) authorize(ctx context.Context, action string, p *model.Product, fields []string) error {
+	if s.authz == nil {
+		return nil
+	}
+	req := policy.Request{Action: action, Fields: fields}
+	if p != nil {
+		req.Resource = &policy.Resource{Category: p.Category}
+		if !p.ID.IsZero() {
+			req.Resource.ID = p.ID.Hex()
+		}
+	}
+	d := s.authz.Authorize(ctx, req)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("authz.action", action),
+		attribute.Bool("authz.allowed", d.Allowed),
+		attribute.String("authz.rule", d.Rule),
+	)
+	if d.Allowed {
+		return nil
+	}
+
+	attrs := []slog.Attr{
+		slog.String("enduser.id", auth.Subject(ctx)),
+		slog.String("authz.action", action),
+		slog.String("authz.roles", strings.Join(d.Roles, ",")),
+		slog.String("authz.rule", d.Rule),
+		slog.String("authz.reason", d.Reason),
+		slog.String("authz.policy_digest", s.authz.Digest()),
+	}
+	if req.Resource != nil {
+		attrs = append(attrs,
+			slog.String("authz.resource.id", req.Resource.ID),
+			slog.String("authz.resource.category", req.Resource.Category),
+		)
+	}
+	if len(d.Fields) > 0 {
+		attrs = append(attrs, slog.String("authz.fields", strings.Join(d.Fields, ",")))
+	}
+	logger.Warn(ctx, "Authorization denied", attrs...)
+	return fmt.Errorf("%w: %s", ErrForbidden, d.Reason)
+}
+
+// changedFields lists the policy fields an update from current to next
+// changes.
+func changedFields(current, next *model.Product) []string {
+	var fields []string
+	if current.Name != next.Name {
+		fields = append(fields, "name")
+	}
+	if current.Price != next.Price {
+		fields = append(fields, "price")
+	}
+	if current.Stock != next.Stock {
+		fields = append(fields, "stock")
+	}
+	if current.Category != next.Category {
+		fields = append(fields, "category")
+	}
+	if !slices.Equal(current.Tags, next.Tags) {
+		fields = append(fields, "tags")
+	}
+	return fields
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/policy"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// syrupReader may read syrups and nothing else, not even the whole catalog.
+const syrupReader = `{
+  "roles": {
+    "syrups": {
+      "rules": [{"name": "read-syrups", "effect": "allow", "actions": ["product:read"], "categories": ["syrup"]}]
+    }
+  }
+}`
+
+type staticAuthorizer struct {
+	policy *policy.Policy
+	roles  []string
+}
+
+func (a staticAuthorizer) Authorize(_ context.Context, req policy.Request) policy.Decision {
+	req.Roles = a.roles
+	return a.policy.Evaluate(req)
+}
+
+func (a staticAuthorizer) Digest() string { return "test" }
+
+// guardedServices wires the services the way the servers do, on mt's mock
+// client, with the caller holding only the syrups role.
+func guardedServices(t *testing.T, mt *mtest.T) (*AttachmentService, *PriceService, *ReportService) {
+	t.Helper()
+	p, err := policy.Parse([]byte(syrupReader))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	db := &database.Mongo{Client: mt.Client, Database: mt.DB}
+
+	products := NewProductService(repository.NewProductRepository(db))
+	products.SetAuthorizer(staticAuthorizer{policy: p, roles: []string{"syrups"}})
+
+	attachments := NewAttachmentService(repository.NewProductRepository(db), nil, AttachmentConfig{})
+	attachments.SetProductGuard(products)
+	// One createIndexes per price collection.
+	mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+	priceRepo, err := repository.NewPriceRepository(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewPriceRepository: %v", err)
+	}
+	prices := NewPriceService(priceRepo, repository.NewProductRepository(db))
+	prices.SetProductGuard(products)
+	// Reports are denied before the repository is touched.
+	reports := NewReportService(nil, time.Minute, time.Second)
+	reports.SetProductGuard(products)
+	return attachments, prices, reports
+}
+
+func productDoc(id primitive.ObjectID, category string) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "name", Value: "test"},
+		{Key: "category", Value: category},
+		{Key: "attachments", Value: bson.A{bson.D{{Key: "_id", Value: "a1"}}}},
+	}
+}
+
+// found answers the next FindOne with doc, or with no document if doc is nil.
+func found(mt *mtest.T, doc bson.D) {
+	ns := mt.DB.Name() + ".product"
+	if doc == nil {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+		return
+	}
+	mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc))
+}
+
+func TestReadAuthorization(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+
+	mt.Run("attachments of a denied product", func(mt *mtest.T) {
+		attachments, _, _ := guardedServices(t, mt)
+		found(mt, productDoc(id, "juice"))
+		if _, err := attachments.List(ctx, id.Hex()); !errors.Is(err, ErrForbidden) {
+			t.Errorf("List error = %v, want ErrForbidden", err)
+		}
+		found(mt, productDoc(id, "juice"))
+		if _, _, err := attachments.Open(ctx, id.Hex(), "a1"); !errors.Is(err, ErrForbidden) {
+			t.Errorf("Open error = %v, want ErrForbidden", err)
+		}
+	})
+
+	mt.Run("attachments of an allowed product", func(mt *mtest.T) {
+		attachments, _, _ := guardedServices(t, mt)
+		found(mt, productDoc(id, "syrup"))
+		got, err := attachments.List(ctx, id.Hex())
+		if err != nil {
+			t.Fatalf("List error = %v", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("List = %v, want one attachment", got)
+		}
+	})
+
+	mt.Run("prices of a denied product", func(mt *mtest.T) {
+		_, prices, _ := guardedServices(t, mt)
+		found(mt, productDoc(id, "juice"))
+		if _, err := prices.GetSchedules(ctx, id.Hex()); !errors.Is(err, ErrForbidden) {
+			t.Errorf("GetSchedules error = %v, want ErrForbidden", err)
+		}
+		found(mt, productDoc(id, "juice"))
+		if _, err := prices.GetHistory(ctx, id.Hex(), time.Time{}, time.Time{}); !errors.Is(err, ErrForbidden) {
+			t.Errorf("GetHistory error = %v, want ErrForbidden", err)
+		}
+	})
+
+	mt.Run("prices of a deleted product are judged by ID", func(mt *mtest.T) {
+		_, prices, _ := guardedServices(t, mt)
+		found(mt, nil)
+		if _, err := prices.GetHistory(ctx, id.Hex(), time.Time{}, time.Time{}); !errors.Is(err, ErrForbidden) {
+			t.Errorf("GetHistory error = %v, want ErrForbidden", err)
+		}
+	})
+
+	mt.Run("prices of an allowed product", func(mt *mtest.T) {
+		_, prices, _ := guardedServices(t, mt)
+		found(mt, productDoc(id, "syrup"))
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".price_schedule", mtest.FirstBatch))
+		if _, err := prices.GetSchedules(ctx, id.Hex()); err != nil {
+			t.Errorf("GetSchedules error = %v", err)
+		}
+	})
+
+	mt.Run("reports need a catalog read", func(mt *mtest.T) {
+		_, _, reports := guardedServices(t, mt)
+		checks := map[string]func() error{
+			"InventoryValue": func() error { _, err := reports.InventoryValue(ctx); return err },
+			"LowStock":       func() error { _, err := reports.LowStock(ctx, 5, 10); return err },
+			"PriceHistogram": func() error { _, err := reports.PriceHistogram(ctx, nil, 4); return err },
+			"CountBy":        func() error { _, err := reports.CountBy(ctx, "category"); return err },
+		}
+		for name, check := range checks {
+			if err := check(); !errors.Is(err, ErrForbidden) {
+				t.Errorf("%s error = %v, want ErrForbidden", name, err)
+			}
+		}
+	})
+}
@@ -2,44 +2,75 @@ package service
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
+	"simple-crud/internal/health"
 	"simple-crud/internal/logger"
 
 	"go.opentelemetry.io/otel"
-
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type HealthService struct {
-	Mongo *mongo.Client
-}
-
-type HealthStatus struct {
-	Mongo string
+	registry *health.Registry
 }
 
 var HealthServiceTracer = otel.Tracer("HealthService")
 
-func NewHealthService(mongo *mongo.Client) *HealthService {
+func NewHealthService(registry *health.Registry) *HealthService {
 	return &HealthService{
-		Mongo: mongo,
+		registry: registry,
 	}
 }
 
-func (s *HealthService) Check(ctx context.Context) HealthStatus {
-	ctx, span := HealthServiceTracer.Start(ctx, "HealthService.Check")
+// Live reports whether the process should keep running.
+func (s *HealthService) Live(ctx context.Context) health.Report {
+	ctx, span := HealthServiceTracer.Start(ctx, "HealthService.Live")
 	defer span.End()
-	logger.Info(ctx, "HealthService.Check")
+	logger.Info(ctx, "HealthService.Live")
 
-	status := HealthStatus{Mongo: "UP"}
+	return s.registry.Live(ctx)
+}
 
-	// MongoDB
-	mongoCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
-	if err := s.Mongo.Ping(mongoCtx, nil); err != nil {
-		status.Mongo = "DOWN"
-	}
+// Ready reports whether the process should receive traffic.
+func (s *HealthService) Ready(ctx context.Context) health.Report {
+	ctx, span := HealthServiceTracer.Start(ctx, "HealthService.Ready")
+	defer span.End()
+	logger.Info(ctx, "HealthService.Ready")
 
-	return status
+	return s.registry.Ready(ctx)
+}
+
+// Startup reports whether the process has finished starting.
+func (s *HealthService) Startup(ctx context.Context) health.Report {
+	ctx, span := HealthServiceTracer.Start(ctx, "HealthService.Startup")
+	defer span.End()
+	logger.Info(ctx, "HealthService.Startup")
+
+	return s.registry.Startup(ctx)
+}
+
+// WatchReady evaluates readiness every interval until ctx ends, calling fn
+// with the first report and whenever readiness flips. Only the flips are
+// logged.
+func (s *HealthService) WatchReady(ctx context.Context, interval time.Duration, fn func(health.Report)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	first := true
+	var up bool
+	for {
+		rep := s.registry.Ready(ctx)
+		if first || rep.Up() != up {
+			if !first {
+				logger.Info(ctx, "Readiness changed", slog.String("data.status", rep.Status))
+			}
+			first, up = false, rep.Up()
+			fn(rep)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	ErrInvalidSchedule  = errors.New("invalid price schedule")
+	ErrScheduleNotFound = errors.New("price schedule not found")
+	ErrScheduleConflict = errors.New("price schedule overlaps an existing schedule")
+	ErrNoPriceAsOf      = errors.New("no price recorded at that time")
+)
+
+// PriceHistory records price changes and answers point-in-time lookups.
+type PriceHistory interface {
+	RecordPrice(ctx context.Context, productID primitive.ObjectID, price float64)
+	PriceAt(ctx context.Context, productID primitive.ObjectID, at time.Time) (float64, error)
+}
+
+type PriceService struct {
+	prices   *repository.PriceRepository
+	products *repository.ProductRepository
+	guard    ProductGuard
+}
+
+var PriceServiceTracer = otel.Tracer("PriceService")
+
+func NewPriceService(prices *repository.PriceRepository, products *repository.ProductRepository) *PriceService {
+	return &PriceService{prices: prices, products: products}
+}
+
+// SetProductGuard makes reading schedules and history subject to the policy
+// as reads of the product, and creating and cancelling schedules as updates
+// of its price.
+func (s *PriceService) SetProductGuard(g ProductGuard) {
+	s.guard = g
+}
+
+// guarded loads the product the guard judges. A product deleted since is
+// judged by its ID alone.
+func (s *PriceService) guarded(ctx context.Context, id primitive.ObjectID) (*model.Product, error) {
+	product, err := s.products.FindByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &model.Product{ID: id}, nil
+	}
+	return product, err
+}
+
+func (s *PriceService) authorizeRead(ctx context.Context, id primitive.ObjectID) error {
+	if s.guard == nil {
+		return nil
+	}
+	product, err := s.guarded(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.guard.AuthorizeRead(ctx, product)
+}
+
+func (s *PriceService) authorize(ctx context.Context, p *model.Product) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.AuthorizeChange(ctx, p, "price")
+}
+
+// parseObjectID parses a product or schedule ID.
+func parseObjectID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: invalid ID format", ErrInvalidSchedule)
+	}
+	return objID, nil
+}
+
+// overlaps reports whether [aStart, aEnd) and [bStart, bEnd) intersect; a
+// nil end never ends.
+func overlaps(aStart time.Time, aEnd *time.Time, bStart time.Time, bEnd *time.Time) bool {
+	return (aEnd == nil || bStart.Before(*aEnd)) && (bEnd == nil || aStart.Before(*bEnd))
+}
+
+// CreateSchedule plans a price change for the product. Without EndsAt the
+// change is permanent; with it the previous price is restored at EndsAt.
+// Schedules of one product may not overlap, so every revert restores the
+// price that was set before its own schedule started.
+func (s *PriceService) CreateSchedule(ctx context.Context, id string, sched *model.PriceSchedule) (*model.PriceSchedule, error) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.CreateSchedule")
+	defer span.End()
+	logger.Info(ctx, "PriceService.CreateSchedule")
+
+	objID, err := parseObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	var v validator
+	v.check(sched.Price > 0, "price", "must be positive")
+	v.check(!sched.StartsAt.IsZero(), "starts_at", "is required")
+	if sched.EndsAt != nil {
+		v.check(sched.EndsAt.After(sched.StartsAt), "ends_at", "must be after starts_at")
+		v.check(sched.EndsAt.After(now), "ends_at", "is in the past")
+	}
+	if err := v.err(ErrInvalidSchedule); err != nil {
+		return nil, err
+	}
+	product, err := s.products.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorize(ctx, product); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.prices.FindSchedules(ctx, objID, model.ScheduleScheduled, model.ScheduleActive)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		if overlaps(p.StartsAt, p.EndsAt, sched.StartsAt, sched.EndsAt) {
+			return nil, fmt.Errorf("%w: %s", ErrScheduleConflict, p.ID.Hex())
+		}
+	}
+
+	sched.ProductID = objID
+	sched.StartsAt = sched.StartsAt.UTC()
+	if sched.EndsAt != nil {
+		end := sched.EndsAt.UTC()
+		sched.EndsAt = &end
+	}
+	sched.Status = model.ScheduleScheduled
+	sched.PreviousPrice = nil
+	sched.AppliedAt, sched.CompletedAt = nil, nil
+	sched.CreatedAt = now
+	if err := s.prices.InsertSchedule(ctx, sched); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+func (s *PriceService) GetSchedules(ctx context.Context, id string) ([]model.PriceSchedule, error) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.GetSchedules")
+	defer span.End()
+	logger.Info(ctx, "PriceService.GetSchedules")
+
+	objID, err := parseObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(ctx, objID); err != nil {
+		return nil, err
+	}
+	return s.prices.FindSchedules(ctx, objID)
+}
+
+// CancelSchedule drops a schedule that has not started. An active schedule
+// is ended now instead, so the scheduler restores the previous price.
+func (s *PriceService) CancelSchedule(ctx context.Context, id string) (*model.PriceSchedule, error) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.CancelSchedule")
+	defer span.End()
+	logger.Info(ctx, "PriceService.CancelSchedule")
+
+	objID, err := parseObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.guard != nil {
+		if err := s.authorizeCancel(ctx, objID); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now().UTC()
+	sched, err := s.prices.TransitionSchedule(ctx, objID, model.ScheduleScheduled, bson.M{
+		"status":       model.ScheduleCancelled,
+		"completed_at": now,
+	})
+	if err == nil {
+		return sched, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	sched, err = s.prices.TransitionSchedule(ctx, objID, model.ScheduleActive, bson.M{"ends_at": now})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrScheduleNotFound
+	}
+	return sched, err
+}
+
+// authorizeCancel authorizes cancelling the schedule against its product.
+func (s *PriceService) authorizeCancel(ctx context.Context, id primitive.ObjectID) error {
+	sched, err := s.prices.FindScheduleByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrScheduleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	product, err := s.guarded(ctx, sched.ProductID)
+	if err != nil {
+		return err
+	}
+	return s.authorize(ctx, product)
+}
+
+// GetHistory returns the prices effective between from and to; zero bounds
+// are open.
+func (s *PriceService) GetHistory(ctx context.Context, id string, from, to time.Time) ([]model.PriceChange, error) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.GetHistory")
+	defer span.End()
+	logger.Info(ctx, "PriceService.GetHistory")
+
+	objID, err := parseObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidSchedule)
+	}
+	if err := s.authorizeRead(ctx, objID); err != nil {
+		return nil, err
+	}
+	return s.prices.FindHistory(ctx, objID, from, to)
+}
+
+// RecordPrice adds a manual change unless the price is already current. It
+// never fails the caller: the product write stands even if history lags.
+func (s *PriceService) RecordPrice(ctx context.Context, productID primitive.ObjectID, price float64) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.RecordPrice")
+	defer span.End()
+	logger.Info(ctx, "PriceService.RecordPrice")
+
+	now := time.Now().UTC()
+	current, err := s.prices.PriceAt(ctx, productID, now)
+	if err == nil && current.Price == price {
+		return
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error(ctx, "Failed to read price history", slog.String("exception.message", err.Error()))
+		return
+	}
+	err = s.prices.InsertPriceChange(ctx, &model.PriceChange{
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: now,
+		Source:        model.PriceSourceManual,
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to record price change", slog.String("exception.message", err.Error()))
+	}
+}
+
+// PriceAt returns the price in effect at `at`.
+func (s *PriceService) PriceAt(ctx context.Context, productID primitive.ObjectID, at time.Time) (float64, error) {
+	ctx, span := PriceServiceTracer.Start(ctx, "PriceService.PriceAt")
+	defer span.End()
+	logger.Info(ctx, "PriceService.PriceAt")
+
+	c, err := s.prices.PriceAt(ctx, productID, at)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, ErrNoPriceAsOf
+		}
+		return 0, err
+	}
+	return c.Price, nil
+}
@@ -3,35 +3,97 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"simple-crud/internal/logger"
 	"simple-crud/internal/model"
+	"simple-crud/internal/policy"
 	"simple-crud/internal/repository"
+	"simple-crud/internal/storage"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel"
 )
 
+var (
+	ErrInvalidProduct   = errors.New("invalid product data")
+	ErrInvalidProductID = errors.New("invalid ID format")
+)
+
 type ProductService struct {
-	repo *repository.ProductRepository
+	repo   *repository.ProductRepository
+	events EventPublisher
+	blobs  storage.BlobStore
+	prices PriceHistory
+	authz  Authorizer
 }
 
 var ProductServiceTracer = otel.Tracer("ProductService")
 
+const (
+	DefaultStreamChunkSize = 100
+	MaxStreamChunkSize     = 1000
+)
+
 func NewProductService(repo *repository.ProductRepository) *ProductService {
 	return &ProductService{repo: repo}
 }
 
+// SetEventPublisher registers p to be notified of product changes.
+func (s *ProductService) SetEventPublisher(p EventPublisher) {
+	s.events = p
+}
+
+// SetBlobStore lets Delete remove the attachments of deleted products.
+func (s *ProductService) SetBlobStore(store storage.BlobStore) {
+	s.blobs = store
+}
+
+// SetPriceHistory records price changes made through Create and Update and
+// enables GetByIDAsOf.
+func (s *ProductService) SetPriceHistory(h PriceHistory) {
+	s.prices = h
+}
+
+// SetAuthorizer makes every product action subject to a's policy. Without
+// one, only the credential scopes checked by the transports apply.
+func (s *ProductService) SetAuthorizer(a Authorizer) {
+	s.authz = a
+}
+
+func (s *ProductService) publish(ctx context.Context, event string, data any) {
+	if s.events != nil {
+		s.events.Publish(ctx, event, data)
+	}
+}
+
 func (s *ProductService) Create(ctx context.Context, p *model.Product) (*model.Product, error) {
 	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.Create")
 	defer span.End()
 	logger.Info(ctx, "ProductService.Create")
 
-	if p.Name == "" || p.Price <= 0 || p.Stock < 0 {
-		return nil, errors.New("invalid product data")
+	var v validator
+	v.check(p.Name != "", "name", "is required")
+	v.check(p.Price > 0, "price", "must be positive")
+	v.check(p.Stock >= 0, "stock", "must not be negative")
+	if err := v.err(ErrInvalidProduct); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, policy.ActionProductCreate, p, nil); err != nil {
+		return nil, err
+	}
+	// Attachments are only added through the upload endpoint.
+	p.Attachments = nil
+	if err := s.repo.Insert(ctx, p); err != nil {
+		return p, err
+	}
+	if s.prices != nil {
+		s.prices.RecordPrice(ctx, p.ID, p.Price)
 	}
-	err := s.repo.Insert(ctx, p)
-	return p, err
+	s.publish(ctx, model.EventProductCreated, p)
+	return p, nil
 }
 
 func (s *ProductService) GetAll(ctx context.Context) ([]model.Product, error) {
@@ -39,9 +101,45 @@ func (s *ProductService) GetAll(ctx context.Context) ([]model.Product, error) {
 	defer span.End()
 	logger.Info(ctx, "ProductService.GetAll")
 
+	if err := s.authorize(ctx, policy.ActionProductRead, nil, nil); err != nil {
+		return nil, err
+	}
 	return s.repo.FindAll(ctx)
 }
 
+// ChangeMarker returns the catalog's change marker, which validates a
+// GetAll listing without reading it; fetch it before listing.
+func (s *ProductService) ChangeMarker(ctx context.Context) (*model.ChangeMarker, error) {
+	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.ChangeMarker")
+	defer span.End()
+	logger.Info(ctx, "ProductService.ChangeMarker")
+
+	if err := s.authorize(ctx, policy.ActionProductRead, nil, nil); err != nil {
+		return nil, err
+	}
+	return s.repo.ChangeMarker(ctx)
+}
+
+// Stream delivers all products to fn in chunks of chunkSize. A non-positive
+// chunkSize selects DefaultStreamChunkSize; larger values are capped at
+// MaxStreamChunkSize to keep each message well under gRPC's size limit.
+func (s *ProductService) Stream(ctx context.Context, chunkSize int, fn func([]model.Product) error) error {
+	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.Stream")
+	defer span.End()
+	logger.Info(ctx, "ProductService.Stream")
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamChunkSize
+	}
+	if chunkSize > MaxStreamChunkSize {
+		chunkSize = MaxStreamChunkSize
+	}
+	if err := s.authorize(ctx, policy.ActionProductRead, nil, nil); err != nil {
+		return err
+	}
+	return s.repo.Stream(ctx, chunkSize, fn)
+}
+
 func (s *ProductService) GetByID(ctx context.Context, id string) (*model.Product, error) {
 	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.GetByID")
 	defer span.End()
@@ -49,21 +147,90 @@ func (s *ProductService) GetByID(ctx context.Context, id string) (*model.Product
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid ID format")
+		return nil, ErrInvalidProductID
+	}
+	p, err := s.repo.FindByID(ctx, objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
-	return s.repo.FindByID(ctx, objID)
+	if err := s.authorize(ctx, policy.ActionProductRead, p, nil); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
-func (s *ProductService) Update(ctx context.Context, id string, p *model.Product) error {
+// GetByIDAsOf returns the product with the price that was in effect at asOf.
+// Other fields are current; only prices are versioned.
+func (s *ProductService) GetByIDAsOf(ctx context.Context, id string, asOf time.Time) (*model.Product, error) {
+	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.GetByIDAsOf")
+	defer span.End()
+	logger.Info(ctx, "ProductService.GetByIDAsOf")
+
+	if s.prices == nil {
+		return nil, errors.New("price history is not enabled")
+	}
+	p, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	price, err := s.prices.PriceAt(ctx, p.ID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	p.Price = price
+	return p, nil
+}
+
+// Update replaces the product's editable fields and returns the product as
+// stored.
+func (s *ProductService) Update(ctx context.Context, id string, p *model.Product) (*model.Product, error) {
 	ctx, span := ProductServiceTracer.Start(ctx, "ProductService.Update")
 	defer span.End()
 	logger.Info(ctx, "ProductService.Update")
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid ID format")
+		return nil, ErrInvalidProductID
+	}
+	// The policy judges the fields that change, and the product as it is
+	// and as it would be, so a category rule cannot be escaped by moving
+	// the product out of (or into) the category.
+	if s.authz != nil {
+		current, err := s.repo.FindByID(ctx, objID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProductNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := changedFields(current, p)
+		if err := s.authorize(ctx, policy.ActionProductUpdate, current, fields); err != nil {
+			return nil, err
+		}
+		if p.Category != current.Category {
+			next := *p
+			next.ID = objID
+			if err := s.authorize(ctx, policy.ActionProductUpdate, &next, fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// History and webhooks follow only a write that found the product.
+	updated, err := s.repo.Update(ctx, objID, p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
-	return s.repo.Update(ctx, objID, p)
+	if s.prices != nil {
+		s.prices.RecordPrice(ctx, objID, updated.Price)
+	}
+	s.publish(ctx, model.EventProductUpdated, updated)
+	return updated, nil
 }
 
 func (s *ProductService) Delete(ctx context.Context, id string) error {
@@ -73,7 +240,44 @@ func (s *ProductService) Delete(ctx context.Context, id string) error {
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid ID format")
+		return ErrInvalidProductID
+	}
+	// The policy needs the product; attachment cleanup is best effort.
+	var current *model.Product
+	if s.authz != nil || s.blobs != nil {
+		p, err := s.repo.FindByID(ctx, objID)
+		switch {
+		case err == nil:
+			current = p
+		case s.authz == nil:
+			// Only the attachments were wanted; delete anyway.
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return ErrProductNotFound
+		default:
+			return err
+		}
+	}
+	if err := s.authorize(ctx, policy.ActionProductDelete, current, nil); err != nil {
+		return err
+	}
+	var attachments []model.Attachment
+	if s.blobs != nil && current != nil {
+		attachments = current.Attachments
+	}
+	if err := s.repo.Delete(ctx, objID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+	for _, a := range attachments {
+		if err := s.blobs.Delete(ctx, a.ID); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			logger.Error(ctx, "Failed to delete attachment blob",
+				slog.String("data.attachment_id", a.ID),
+				slog.String("exception.message", err.Error()),
+			)
+		}
 	}
-	return s.repo.Delete(ctx, objID)
+	s.publish(ctx, model.EventProductDeleted, map[string]string{"id": id})
+	return nil
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"simple-crud/internal/cache"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DefaultLowStockThreshold = 10
+	DefaultCountBy           = "category"
+
+	DefaultLowStockLimit   = 100
+	MaxLowStockLimit       = 1000
+	DefaultHistogramBucket = 10
+	MaxHistogramBuckets    = 100
+)
+
+var ErrInvalidReportParams = errors.New("invalid report parameters")
+
+func invalidReportParam(field, message string) error {
+	return &ValidationError{Err: ErrInvalidReportParams, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+type ReportService struct {
+	repo    *repository.ReportRepository
+	cache   *cache.TTL[any]
+	timeout time.Duration
+	guard   ProductGuard
+}
+
+var ReportServiceTracer = otel.Tracer("ReportService")
+
+func NewReportService(repo *repository.ReportRepository, cacheTTL, timeout time.Duration) *ReportService {
+	return &ReportService{
+		repo:    repo,
+		cache:   cache.NewTTL[any](cacheTTL),
+		timeout: timeout,
+	}
+}
+
+// SetProductGuard makes every report subject to the policy as a read of the
+// whole catalog, since reports span products of every category.
+func (s *ReportService) SetProductGuard(g ProductGuard) {
+	s.guard = g
+}
+
+func (s *ReportService) authorize(ctx context.Context) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.AuthorizeRead(ctx, nil)
+}
+
+// CacheTTL is how long a report result may be reused; handlers advertise it
+// to clients as Cache-Control max-age.
+func (s *ReportService) CacheTTL() time.Duration {
+	return s.cache.TTL()
+}
+
+// cached returns the cached value for key or computes it with load under the
+// report timeout. The context's own deadline wins if it is sooner.
+func cached[T any](ctx context.Context, s *ReportService, key string, load func(context.Context) (T, error)) (T, error) {
+	span := trace.SpanFromContext(ctx)
+	if v, ok := s.cache.Get(key); ok {
+		span.SetAttributes(attribute.Bool("report.cache_hit", true))
+		return v.(T), nil
+	}
+	span.SetAttributes(attribute.Bool("report.cache_hit", false))
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	v, err := load(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	s.cache.Set(key, v)
+	return v, nil
+}
+
+func (s *ReportService) InventoryValue(ctx context.Context) (*model.InventoryValue, error) {
+	ctx, span := ReportServiceTracer.Start(ctx, "ReportService.InventoryValue")
+	defer span.End()
+	logger.Info(ctx, "ReportService.InventoryValue")
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return cached(ctx, s, "inventory-value", s.repo.InventoryValue)
+}
+
+func (s *ReportService) LowStock(ctx context.Context, threshold int, limit int64) ([]model.Product, error) {
+	ctx, span := ReportServiceTracer.Start(ctx, "ReportService.LowStock")
+	defer span.End()
+	logger.Info(ctx, "ReportService.LowStock")
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if threshold < 0 {
+		return nil, invalidReportParam("threshold", "must not be negative")
+	}
+	if limit <= 0 {
+		limit = DefaultLowStockLimit
+	}
+	if limit > MaxLowStockLimit {
+		limit = MaxLowStockLimit
+	}
+
+	key := fmt.Sprintf("low-stock:%d:%d", threshold, limit)
+	return cached(ctx, s, key, func(ctx context.Context) ([]model.Product, error) {
+		return s.repo.LowStock(ctx, threshold, limit)
+	})
+}
+
+func (s *ReportService) PriceHistogram(ctx context.Context, boundaries []float64, buckets int) ([]model.PriceBucket, error) {
+	ctx, span := ReportServiceTracer.Start(ctx, "ReportService.PriceHistogram")
+	defer span.End()
+	logger.Info(ctx, "ReportService.PriceHistogram")
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if len(boundaries) == 1 {
+		return nil, invalidReportParam("boundaries", "at least two boundaries are required")
+	}
+	boundaries = append([]float64(nil), boundaries...)
+	sort.Float64s(boundaries)
+	for i := 1; i < len(boundaries); i++ {
+		if boundaries[i] == boundaries[i-1] {
+			return nil, invalidReportParam("boundaries", fmt.Sprintf("duplicate boundary %v", boundaries[i]))
+		}
+	}
+	if buckets <= 0 {
+		buckets = DefaultHistogramBucket
+	}
+	if buckets > MaxHistogramBuckets {
+		return nil, invalidReportParam("buckets", fmt.Sprintf("at most %d buckets", MaxHistogramBuckets))
+	}
+
+	key := fmt.Sprintf("price-histogram:%v:%d", boundaries, buckets)
+	return cached(ctx, s, key, func(ctx context.Context) ([]model.PriceBucket, error) {
+		return s.repo.PriceHistogram(ctx, boundaries, buckets)
+	})
+}
+
+func (s *ReportService) CountBy(ctx context.Context, by string) ([]model.GroupCount, error) {
+	ctx, span := ReportServiceTracer.Start(ctx, "ReportService.CountBy")
+	defer span.End()
+	logger.Info(ctx, "ReportService.CountBy")
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return cached(ctx, s, "count-by:"+by, func(ctx context.Context) ([]model.GroupCount, error) {
+		groups, err := s.repo.CountBy(ctx, by)
+		if errors.Is(err, repository.ErrInvalidGroupBy) {
+			return nil, invalidReportParam("by", err.Error())
+		}
+		return groups, err
+	})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"strings"
+)
+
+// FieldError is one failed validation rule on an input field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every invalid field of an input. It unwraps to the
+// service's sentinel (ErrInvalidProduct, ...), so errors.Is keeps working.
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return e.Err.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validator collects field errors; err returns nil when there are none.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+func (v *validator) err(sentinel error) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Err: sentinel, Fields: v.fields}
+}
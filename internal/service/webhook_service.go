@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var (
+	ErrInvalidWebhook  = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// EventPublisher is notified after products change.
+type EventPublisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers.
+type WebhookPayload struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookService struct {
+	repo *repository.WebhookRepository
+}
+
+var WebhookServiceTracer = otel.Tracer("WebhookService")
+
+func NewWebhookService(repo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+func validateSubscription(sub *model.WebhookSubscription) error {
+	var v validator
+	u, err := url.Parse(sub.URL)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+		"url", "must be an absolute http(s) URL")
+	v.check(len(sub.Events) > 0, "events", "at least one event is required")
+	for i, e := range sub.Events {
+		v.check(slices.Contains(model.WebhookEvents, e),
+			fmt.Sprintf("events[%d]", i), fmt.Sprintf("unknown event %q", e))
+	}
+	return v.err(ErrInvalidWebhook)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func webhookID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: invalid ID format", ErrInvalidWebhook)
+	}
+	return objID, nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// Create stores a subscription. A secret is generated when none is given;
+// it is only returned here, later reads hide it.
+func (s *WebhookService) Create(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.Create")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.Create")
+
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+	now := time.Now().UTC()
+	sub.CreatedAt, sub.UpdatedAt = now, now
+
+	if err := s.repo.InsertSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.GetAll")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.GetAll")
+
+	subs, err := s.repo.FindSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.GetByID")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.GetByID")
+
+	objID, err := webhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.FindSubscriptionByID(ctx, objID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// Update replaces url, events and the disabled flag. An empty secret keeps the
+// current one.
+func (s *WebhookService) Update(ctx context.Context, id string, sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.Update")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.Update")
+
+	objID, err := webhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.FindSubscriptionByID(ctx, objID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if sub.Secret == "" {
+		sub.Secret = current.Secret
+	}
+	sub.ID = objID
+	sub.CreatedAt = current.CreatedAt
+	sub.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateSubscription(ctx, objID, sub); err != nil {
+		return nil, notFound(err)
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.Delete")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.Delete")
+
+	objID, err := webhookID(id)
+	if err != nil {
+		return err
+	}
+	return notFound(s.repo.DeleteSubscription(ctx, objID))
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID, status string, limit int64) ([]model.WebhookDelivery, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.GetDeliveries")
+
+	objID, err := webhookID(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" && status != model.DeliveryPending && status != model.DeliveryDelivered && status != model.DeliveryDead {
+		return nil, fmt.Errorf("%w: unknown delivery status %q", ErrInvalidWebhook, status)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.FindDeliveries(ctx, objID, status, limit)
+}
+
+// RetryDelivery puts a dead-lettered delivery back in the queue.
+func (s *WebhookService) RetryDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.RetryDelivery")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.RetryDelivery")
+
+	objID, err := webhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.RequeueDelivery(ctx, objID, time.Now().UTC())
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return delivery, nil
+}
+
+// Publish enqueues one delivery per subscription listening to event. It
+// never fails the caller: errors are logged and the product write stands.
+func (s *WebhookService) Publish(ctx context.Context, event string, data any) {
+	ctx, span := WebhookServiceTracer.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+	logger.Info(ctx, "WebhookService.Publish", slog.String("data.event", event))
+
+	subs, err := s.repo.FindSubscriptionsForEvent(ctx, event)
+	if err != nil {
+		logger.Error(ctx, "Failed to load webhook subscriptions", slog.String("exception.message", err.Error()))
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	body, err := json.Marshal(WebhookPayload{
+		ID:        primitive.NewObjectID().Hex(),
+		Event:     event,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to encode webhook payload", slog.String("exception.message", err.Error()))
+		return
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	deliveries := make([]model.WebhookDelivery, 0, len(subs))
+	for _, sub := range subs {
+		deliveries = append(deliveries, model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			Event:          event,
+			Payload:        string(body),
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  now,
+			TraceContext:   carrier,
+			CreatedAt:      now,
+		})
+	}
+	if err := s.repo.InsertDeliveries(ctx, deliveries); err != nil {
+		logger.Error(ctx, "Failed to enqueue webhook deliveries", slog.String("exception.message", err.Error()))
+	}
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/database"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Blob is an open stored object. It is seekable so HTTP range requests can
+// be served straight from it.
+type Blob interface {
+	io.ReadSeekCloser
+	Size() int64
+}
+
+// BlobStore keeps opaque binary objects under caller-chosen IDs. Metadata
+// (name, content type) lives with the owning document, not in the store.
+type BlobStore interface {
+	Put(ctx context.Context, id, name string, r io.Reader) (int64, error)
+	Open(ctx context.Context, id string) (Blob, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// NewBlobStore builds the store selected by cfg.AttachmentStore.
+func NewBlobStore(cfg *config.Config, db *database.Mongo) (BlobStore, error) {
+	switch cfg.AttachmentStore {
+	case "gridfs":
+		return NewGridFSStore(db, "product_files")
+	case "local":
+		return NewLocalStore(cfg.AttachmentLocalDir)
+	default:
+		return nil, fmt.Errorf("unknown attachment store %q", cfg.AttachmentStore)
+	}
+}
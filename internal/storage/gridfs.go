@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"simple-crud/internal/database"
+	"simple-crud/internal/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+)
+
+// GridFSStore keeps blobs in a GridFS bucket. IDs must be ObjectID hex
+// strings.
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+var GridFSStoreTracer = otel.Tracer("GridFSStore")
+
+func NewGridFSStore(db *database.Mongo, bucketName string) (*GridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db.Database, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSStore{bucket: bucket}, nil
+}
+
+func fileID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, ErrBlobNotFound
+	}
+	return objID, nil
+}
+
+// deadline maps the context deadline onto GridFS stream deadlines, since the
+// stream API does not take a context.
+func deadline(ctx context.Context) time.Time {
+	d, _ := ctx.Deadline()
+	return d
+}
+
+func (s *GridFSStore) Put(ctx context.Context, id, name string, r io.Reader) (int64, error) {
+	ctx, span := GridFSStoreTracer.Start(ctx, "GridFSStore.Put")
+	defer span.End()
+	logger.Info(ctx, "GridFSStore.Put")
+
+	objID, err := fileID(id)
+	if err != nil {
+		return 0, err
+	}
+	stream, err := s.bucket.OpenUploadStreamWithID(objID, name)
+	if err != nil {
+		return 0, err
+	}
+	if err := stream.SetWriteDeadline(deadline(ctx)); err != nil {
+		_ = stream.Abort()
+		return 0, err
+	}
+	n, err := io.Copy(stream, r)
+	if err != nil {
+		_ = stream.Abort()
+		return n, err
+	}
+	return n, stream.Close()
+}
+
+func (s *GridFSStore) Open(ctx context.Context, id string) (Blob, error) {
+	ctx, span := GridFSStoreTracer.Start(ctx, "GridFSStore.Open")
+	defer span.End()
+	logger.Info(ctx, "GridFSStore.Open")
+
+	objID, err := fileID(id)
+	if err != nil {
+		return nil, err
+	}
+	b := &gridFSBlob{bucket: s.bucket, id: objID, deadline: deadline(ctx)}
+	if err := b.open(); err != nil {
+		return nil, err
+	}
+	b.size = b.stream.GetFile().Length
+	return b, nil
+}
+
+func (s *GridFSStore) Delete(ctx context.Context, id string) error {
+	ctx, span := GridFSStoreTracer.Start(ctx, "GridFSStore.Delete")
+	defer span.End()
+	logger.Info(ctx, "GridFSStore.Delete")
+
+	objID, err := fileID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.bucket.DeleteContext(ctx, objID); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return ErrBlobNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// gridFSBlob makes a GridFS download seekable. GridFS streams only move
+// forward, so seeking backwards (or anywhere, before the first read) reopens
+// the stream and skips to the new offset.
+type gridFSBlob struct {
+	bucket   *gridfs.Bucket
+	id       primitive.ObjectID
+	deadline time.Time
+	size     int64
+
+	stream    *gridfs.DownloadStream
+	streamPos int64
+	offset    int64
+}
+
+func (b *gridFSBlob) open() error {
+	if b.stream != nil {
+		_ = b.stream.Close()
+		b.stream = nil
+	}
+	stream, err := b.bucket.OpenDownloadStream(b.id)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return ErrBlobNotFound
+		}
+		return err
+	}
+	if err := stream.SetReadDeadline(b.deadline); err != nil {
+		_ = stream.Close()
+		return err
+	}
+	b.stream, b.streamPos = stream, 0
+	return nil
+}
+
+func (b *gridFSBlob) Size() int64 { return b.size }
+
+func (b *gridFSBlob) Read(p []byte) (int, error) {
+	if b.offset >= b.size {
+		return 0, io.EOF
+	}
+	if b.stream == nil || b.streamPos > b.offset {
+		if err := b.open(); err != nil {
+			return 0, err
+		}
+	}
+	if b.streamPos < b.offset {
+		skipped, err := b.stream.Skip(b.offset - b.streamPos)
+		b.streamPos += skipped
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := b.stream.Read(p)
+	b.streamPos += int64(n)
+	b.offset += int64(n)
+	return n, err
+}
+
+func (b *gridFSBlob) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.New("gridfs: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("gridfs: negative position")
+	}
+	b.offset = offset
+	return offset, nil
+}
+
+func (b *gridFSBlob) Close() error {
+	if b.stream == nil {
+		return nil
+	}
+	return b.stream.Close()
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"simple-crud/internal/logger"
+
+	"go.opentelemetry.io/otel"
+)
+
+// LocalStore keeps each blob as a file named by its ID under dir. It suits
+// single-replica deployments and local development.
+type LocalStore struct {
+	dir string
+}
+
+var LocalStoreTracer = otel.Tracer("LocalStore")
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// path rejects IDs that could escape dir.
+func (s *LocalStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", ErrBlobNotFound
+	}
+	return filepath.Join(s.dir, id), nil
+}
+
+// Put writes to a temporary file first so readers never see a partial blob.
+func (s *LocalStore) Put(ctx context.Context, id, name string, r io.Reader) (int64, error) {
+	ctx, span := LocalStoreTracer.Start(ctx, "LocalStore.Put")
+	defer span.End()
+	logger.Info(ctx, "LocalStore.Put")
+
+	path, err := s.path(id)
+	if err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(s.dir, id+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+		return n, err
+	}
+	if err := tmp.Close(); err != nil {
+		return n, err
+	}
+	return n, os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Open(ctx context.Context, id string) (Blob, error) {
+	ctx, span := LocalStoreTracer.Start(ctx, "LocalStore.Open")
+	defer span.End()
+	logger.Info(ctx, "LocalStore.Open")
+
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &localBlob{File: f, size: info.Size()}, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, id string) error {
+	ctx, span := LocalStoreTracer.Start(ctx, "LocalStore.Delete")
+	defer span.End()
+	logger.Info(ctx, "LocalStore.Delete")
+
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrBlobNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+type localBlob struct {
+	*os.File
+	size int64
+}
+
+func (b *localBlob) Size() int64 { return b.size }
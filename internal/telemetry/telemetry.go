@@ -23,6 +23,9 @@ var (
 	once         sync.Once
 	shutdownFunc func()
 	initErr      error
+
+	provider *trace.TracerProvider
+	profiler *pyroscope.Profiler
 )
 
 var pyroLogrus = func() *logrus.Logger {
@@ -79,7 +82,7 @@ func Instance(globalCtx context.Context) (func(), error) {
 		log.Info("OpenTelemetry Tracer initialized")
 
 		// Start Pyroscope profiler agent
-		_, err2 := pyroscope.Start(pyroscope.Config{
+		prof, err2 := pyroscope.Start(pyroscope.Config{
 			ApplicationName: cfg.AppName,
 			ServerAddress:   cfg.RemoteProfilingHttpURI,
 			// TenantID:        cfg.RemoteProfilingTenantId,
@@ -91,6 +94,8 @@ func Instance(globalCtx context.Context) (func(), error) {
 		} else {
 			log.Info("Pyroscope started successfully")
 		}
+		provider = tp
+		profiler = prof
 
 		shutdownFunc = func() {
 			if err := tp.Shutdown(globalCtx); err != nil {
@@ -101,3 +106,16 @@ func Instance(globalCtx context.Context) (func(), error) {
 
 	return shutdownFunc, initErr
 }
+
+// Shutdown flushes the spans still batched and stops the profiler; the
+// lifecycle manager calls it once the servers have stopped. It does nothing
+// if telemetry failed to start.
+func Shutdown(ctx context.Context) error {
+	if profiler != nil {
+		_ = profiler.Stop()
+	}
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"simple-crud/internal/config"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var PriceSchedulerTracer = otel.Tracer("PriceScheduler")
+
+// scheduleLease is how long a replica owns a claimed schedule. A replica that
+// dies mid-step leaves the schedule to be retried once the lease expires.
+const scheduleLease = 30 * time.Second
+
+// PriceScheduler starts and ends price schedules. Each step is idempotent:
+// the previous price is captured once, history entries are upserted per
+// schedule, and status changes are guarded by the expected current status,
+// so a step replayed after a crash or by another replica has no extra effect.
+type PriceScheduler struct {
+	prices   *repository.PriceRepository
+	products *repository.ProductRepository
+	interval time.Duration
+}
+
+func NewPriceScheduler(prices *repository.PriceRepository, products *repository.ProductRepository, cfg *config.Config) *PriceScheduler {
+	return &PriceScheduler{
+		prices:   prices,
+		products: products,
+		interval: time.Duration(cfg.PriceSchedulerIntervalMs) * time.Millisecond,
+	}
+}
+
+// Run polls for due schedules until ctx is cancelled.
+func (s *PriceScheduler) Run(ctx context.Context) {
+	logger.Info(ctx, "Price scheduler started", slog.Int64("data.interval_ms", s.interval.Milliseconds()))
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Price scheduler shutting down")
+			return
+		case <-ticker.C:
+			s.drain(ctx)
+		}
+	}
+}
+
+func (s *PriceScheduler) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		sched, err := s.prices.ClaimDueSchedule(ctx, time.Now().UTC(), scheduleLease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			logger.Error(ctx, "Failed to claim price schedule", slog.String("exception.message", err.Error()))
+			return
+		}
+		s.process(ctx, sched)
+	}
+}
+
+func (s *PriceScheduler) process(ctx context.Context, sched *model.PriceSchedule) {
+	ctx, span := PriceSchedulerTracer.Start(ctx, "PriceScheduler.Process")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("schedule.id", sched.ID.Hex()),
+		attribute.String("schedule.status", sched.Status),
+		attribute.String("product.id", sched.ProductID.Hex()),
+	)
+
+	var err error
+	switch sched.Status {
+	case model.ScheduleScheduled:
+		err = s.apply(ctx, sched)
+	case model.ScheduleActive:
+		err = s.revert(ctx, sched)
+	}
+	if err != nil {
+		// Leave the lease to expire; the step is retried afterwards.
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error(ctx, "Failed to process price schedule",
+			slog.String("data.schedule_id", sched.ID.Hex()),
+			slog.String("exception.message", err.Error()),
+		)
+	}
+}
+
+func (s *PriceScheduler) apply(ctx context.Context, sched *model.PriceSchedule) error {
+	logger.Info(ctx, "PriceScheduler.apply", slog.String("data.schedule_id", sched.ID.Hex()))
+	now := time.Now().UTC()
+
+	product, err := s.products.FindByID(ctx, sched.ProductID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_, err = s.prices.TransitionSchedule(ctx, sched.ID, model.ScheduleScheduled, bson.M{
+			"status":       model.ScheduleCancelled,
+			"completed_at": now,
+		})
+		return ignoreNoDocuments(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only the first attempt captures the price; a retry would otherwise
+	// remember the promotional price as the one to restore.
+	if sched.PreviousPrice == nil {
+		if sched, err = s.prices.SetPreviousPrice(ctx, sched.ID, product.Price); err != nil {
+			return err
+		}
+	}
+	if err := s.products.SetPrice(ctx, sched.ProductID, sched.Price); err != nil {
+		return err
+	}
+	err = s.prices.InsertPriceChange(ctx, &model.PriceChange{
+		ProductID:     sched.ProductID,
+		Price:         sched.Price,
+		EffectiveFrom: now,
+		Source:        model.PriceSourceSchedule,
+		ScheduleID:    &sched.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"status": model.ScheduleActive, "applied_at": now}
+	if sched.EndsAt == nil {
+		set["status"] = model.ScheduleCompleted
+		set["completed_at"] = now
+	}
+	_, err = s.prices.TransitionSchedule(ctx, sched.ID, model.ScheduleScheduled, set)
+	return ignoreNoDocuments(err)
+}
+
+// revert restores the previous price, unless the price was edited while the
+// schedule was active; the manual price then wins.
+func (s *PriceScheduler) revert(ctx context.Context, sched *model.PriceSchedule) error {
+	logger.Info(ctx, "PriceScheduler.revert", slog.String("data.schedule_id", sched.ID.Hex()))
+	now := time.Now().UTC()
+
+	if sched.PreviousPrice != nil {
+		swapped, err := s.products.SwapPrice(ctx, sched.ProductID, sched.Price, *sched.PreviousPrice)
+		if err != nil {
+			return err
+		}
+		if swapped {
+			err = s.prices.InsertPriceChange(ctx, &model.PriceChange{
+				ProductID:     sched.ProductID,
+				Price:         *sched.PreviousPrice,
+				EffectiveFrom: now,
+				Source:        model.PriceSourceRevert,
+				ScheduleID:    &sched.ID,
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			logger.Warn(ctx, "Price changed during schedule; not reverting",
+				slog.String("data.schedule_id", sched.ID.Hex()),
+			)
+		}
+	}
+
+	_, err := s.prices.TransitionSchedule(ctx, sched.ID, model.ScheduleActive, bson.M{
+		"status":       model.ScheduleCompleted,
+		"completed_at": now,
+	})
+	return ignoreNoDocuments(err)
+}
+
+// ignoreNoDocuments treats a lost status guard as success: another replica
+// already finished the step.
+func ignoreNoDocuments(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"simple-crud/internal/client"
+	"simple-crud/internal/config"
+	"simple-crud/internal/logger"
+	"simple-crud/internal/model"
+	"simple-crud/internal/repository"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var WebhookWorkerTracer = otel.Tracer("WebhookWorker")
+
+// Headers sent with each delivery. The signature is
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) so receivers can reject
+// replays with an old timestamp.
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+type WebhookWorkerConfig struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+// NewWebhookWorkerConfig reads the worker settings from cfg.
+func NewWebhookWorkerConfig(cfg *config.Config) WebhookWorkerConfig {
+	return WebhookWorkerConfig{
+		PollInterval: time.Duration(cfg.WebhookPollIntervalMs) * time.Millisecond,
+		Timeout:      time.Duration(cfg.WebhookTimeoutMs) * time.Millisecond,
+		MaxAttempts:  int(cfg.WebhookMaxAttempts),
+		BackoffBase:  time.Duration(cfg.WebhookBackoffBaseMs) * time.Millisecond,
+		BackoffMax:   time.Duration(cfg.WebhookBackoffMaxMs) * time.Millisecond,
+	}
+}
+
+// WebhookWorker sends queued webhook deliveries. Deliveries are claimed with
+// a lease, so any number of replicas can run a worker against the same
+// database.
+type WebhookWorker struct {
+	repo   *repository.WebhookRepository
+	client *client.HTTPClient
+	cfg    WebhookWorkerConfig
+}
+
+func NewWebhookWorker(repo *repository.WebhookRepository, cfg WebhookWorkerConfig) *WebhookWorker {
+	return &WebhookWorker{
+		repo:   repo,
+		client: client.NewHTTPClient("", cfg.Timeout),
+		cfg:    cfg,
+	}
+}
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before retry number attempt (1-based):
+// base * 2^(attempt-1) capped at max, with up to 20% jitter.
+func (w *WebhookWorker) Backoff(attempt int) time.Duration {
+	d := w.cfg.BackoffBase
+	for i := 1; i < attempt && d < w.cfg.BackoffMax; i++ {
+		d *= 2
+	}
+	if d > w.cfg.BackoffMax {
+		d = w.cfg.BackoffMax
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	logger.Info(ctx, "Webhook worker started", slog.Int64("data.poll_interval_ms", w.cfg.PollInterval.Milliseconds()))
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Webhook worker shutting down")
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+		}
+	}
+}
+
+// drain delivers everything that is currently due.
+func (w *WebhookWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := w.repo.ClaimDueDelivery(ctx, time.Now().UTC(), 2*w.cfg.Timeout)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			logger.Error(ctx, "Failed to claim webhook delivery", slog.String("exception.message", err.Error()))
+			return
+		}
+		w.deliver(ctx, delivery)
+	}
+}
+
+func (w *WebhookWorker) deliver(ctx context.Context, d *model.WebhookDelivery) {
+	// Continue the trace of the request that produced the event.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(d.TraceContext))
+	ctx, span := WebhookWorkerTracer.Start(ctx, "WebhookWorker.Deliver")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("webhook.delivery_id", d.ID.Hex()),
+		attribute.String("webhook.event", d.Event),
+		attribute.Int("webhook.attempt", d.Attempts+1),
+	)
+
+	sub, err := w.repo.FindSubscriptionByID(ctx, d.SubscriptionID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Subscription was deleted after the event was queued.
+		w.record(ctx, d, model.DeliveryAttempt{At: time.Now().UTC(), Error: "subscription not found"}, false)
+		return
+	}
+	if err != nil {
+		// The receiver was never called, so this costs no attempt; the
+		// delivery stays pending and is claimed again once its lease ends.
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error(ctx, "Failed to load webhook subscription",
+			slog.String("data.delivery_id", d.ID.Hex()),
+			slog.String("exception.message", err.Error()),
+		)
+		return
+	}
+
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	start := time.Now()
+	resp, err := w.client.DoWithResponse(client.RequestOptions{
+		Method:  "POST",
+		URL:     sub.URL,
+		Body:    body,
+		Context: ctx,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			HeaderWebhookEvent:     d.Event,
+			HeaderWebhookDelivery:  d.ID.Hex(),
+			HeaderWebhookTimestamp: timestamp,
+			HeaderWebhookSignature: Sign(sub.Secret, timestamp, body),
+		},
+	})
+
+	attempt := model.DeliveryAttempt{At: start.UTC(), DurationMs: time.Since(start).Milliseconds()}
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case !resp.IsSuccess():
+		attempt.StatusCode = resp.StatusCode
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", resp.StatusCode)
+	default:
+		attempt.StatusCode = resp.StatusCode
+	}
+	if attempt.Error != "" {
+		span.SetStatus(codes.Error, attempt.Error)
+	}
+	w.record(ctx, d, attempt, attempt.Error == "")
+}
+
+func (w *WebhookWorker) record(ctx context.Context, d *model.WebhookDelivery, attempt model.DeliveryAttempt, ok bool) {
+	attempts := d.Attempts + 1
+	status := model.DeliveryPending
+	next := time.Now().UTC().Add(w.Backoff(attempts))
+	switch {
+	case ok:
+		status = model.DeliveryDelivered
+	case attempts >= w.cfg.MaxAttempts:
+		status = model.DeliveryDead
+		logger.Warn(ctx, "Webhook delivery dead-lettered",
+			slog.String("data.delivery_id", d.ID.Hex()),
+			slog.Int("data.attempts", attempts),
+			slog.String("exception.message", attempt.Error),
+		)
+	}
+
+	if err := w.repo.RecordAttempt(ctx, d.ID, attempt, status, next); err != nil {
+		logger.Error(ctx, "Failed to record webhook attempt", slog.String("exception.message", err.Error()))
+	}
+}